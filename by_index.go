@@ -1,8 +1,11 @@
 package ordmap
 
 import (
+	"cmp"
+	"fmt"
 	"iter"
-	"sort"
+	"reflect"
+	"slices"
 
 	"golang.org/x/exp/maps"
 )
@@ -14,16 +17,16 @@ type ByIndexer[K comparable, V any] interface {
 }
 
 // ByIndex is a helper function for an ordered map to implement an iterator.
+// Entries with the same index, including uninitialized ones, are sorted by key so that the order is deterministic.
 func ByIndex[M ~map[K]V, K comparable, V any](m M, getIndex func(V) int) iter.Seq2[K, V] {
-	// get the keys and sort them by index
-	keys := maps.Keys(m)
-	sort.Slice(keys, func(i, j int) bool {
-		idxI := getIndex(m[keys[i]])
-		idxJ := getIndex(m[keys[j]])
-		return idxI != 0 && // if i is not initialized, it should be at the end
-			(idxJ == 0 || // if j is not initialized, it should be at the end
-				idxI < idxJ) // otherwise, sort by index
-	})
+	return ByIndexFunc(m, getIndex, compareKeysFunc[K]())
+}
+
+// ByIndexFunc is like ByIndex but sorts entries with the same index using a custom comparison function for the keys.
+func ByIndexFunc[M ~map[K]V, K comparable, V any](
+	m M, getIndex func(V) int, compare func(K, K) int,
+) iter.Seq2[K, V] {
+	keys := orderedKeysFunc(m, getIndex, compare)
 
 	return func(yield func(K, V) bool) {
 		for _, k := range keys {
@@ -35,14 +38,14 @@ func ByIndex[M ~map[K]V, K comparable, V any](m M, getIndex func(V) int) iter.Se
 }
 
 // ByIndex returns a sequence of key-value pairs sorted by index.
+// Entries with the same index, including uninitialized ones, are sorted by key so that the order is deterministic.
 func (om OrderedMap[K, V]) ByIndex() iter.Seq2[K, V] {
-	// get the keys and sort them by index
-	keys := maps.Keys(om)
-	sort.Slice(keys, func(i, j int) bool {
-		return om[keys[i]].idx != 0 && // if i is not initialized, it should be at the end
-			(om[keys[j]].idx == 0 || // if j is not initialized, it should be at the end
-				om[keys[i]].idx < om[keys[j]].idx) // otherwise, sort by index
-	})
+	return om.ByIndexFunc(compareKeysFunc[K]())
+}
+
+// ByIndexFunc is like ByIndex but sorts entries with the same index using a custom comparison function for the keys.
+func (om OrderedMap[K, V]) ByIndexFunc(compare func(K, K) int) iter.Seq2[K, V] {
+	keys := orderedKeysFunc(om, getIndex, compare)
 
 	return func(yield func(K, V) bool) {
 		for _, k := range keys {
@@ -51,13 +54,100 @@ func (om OrderedMap[K, V]) ByIndex() iter.Seq2[K, V] {
 			}
 		}
 	}
+}
+
+// orderedKeys returns the keys of the map sorted by index and, for the same index, by key.
+func orderedKeys[M ~map[K]V, K comparable, V any](m M, getIndex func(V) int) []K {
+	return orderedKeysFunc(m, getIndex, compareKeysFunc[K]())
+}
+
+// orderedKeysFunc returns the keys of the map sorted by index and, for the same index, by the comparison function.
+func orderedKeysFunc[M ~map[K]V, K comparable, V any](
+	m M, getIndex func(V) int, compare func(K, K) int,
+) []K {
+	// get the keys and sort them by index
+	keys := maps.Keys(m)
+	slices.SortFunc(keys, func(a, b K) int {
+		if c := compareIndex(getIndex(m[a]), getIndex(m[b])); c != 0 {
+			return c
+		}
+
+		return compare(a, b)
+	})
+
+	return keys
+}
+
+// compareIndex compares two indices in the order of ByIndex.
+// An index of zero means the entry is not initialized, so it comes after all others.
+func compareIndex(a, b int) int {
+	switch {
+	case a == b:
+		return 0
+	case a == 0: // if a is not initialized, it should be at the end
+		return 1
+	case b == 0: // if b is not initialized, it should be at the end
+		return -1
+	case a < b: // otherwise, sort by index
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareKeysFunc returns the function that compares two keys that have the same index.
+// Keys of the predeclared string and number types are compared directly, any other keys with compareKeys.
+func compareKeysFunc[K comparable]() func(K, K) int {
+	switch any(*new(K)).(type) {
+	case string:
+		return any(cmp.Compare[string]).(func(K, K) int)
+	case int:
+		return any(cmp.Compare[int]).(func(K, K) int)
+	case int8:
+		return any(cmp.Compare[int8]).(func(K, K) int)
+	case int16:
+		return any(cmp.Compare[int16]).(func(K, K) int)
+	case int32:
+		return any(cmp.Compare[int32]).(func(K, K) int)
+	case int64:
+		return any(cmp.Compare[int64]).(func(K, K) int)
+	case uint:
+		return any(cmp.Compare[uint]).(func(K, K) int)
+	case uint8:
+		return any(cmp.Compare[uint8]).(func(K, K) int)
+	case uint16:
+		return any(cmp.Compare[uint16]).(func(K, K) int)
+	case uint32:
+		return any(cmp.Compare[uint32]).(func(K, K) int)
+	case uint64:
+		return any(cmp.Compare[uint64]).(func(K, K) int)
+	case uintptr:
+		return any(cmp.Compare[uintptr]).(func(K, K) int)
+	case float32:
+		return any(cmp.Compare[float32]).(func(K, K) int)
+	case float64:
+		return any(cmp.Compare[float64]).(func(K, K) int)
+	default:
+		return compareKeys[K]
+	}
+}
+
+// compareKeys compares two keys that have the same index.
+// Strings and numbers are compared by value, any other keys by their default format.
+func compareKeys[K comparable](a, b K) int {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.IsValid() && vb.IsValid() && va.Kind() == vb.Kind() {
+		switch va.Kind() {
+		case reflect.String:
+			return cmp.Compare(va.String(), vb.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(va.Int(), vb.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(va.Uint(), vb.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(va.Float(), vb.Float())
+		}
+	}
 
-	// NOTE: The above is equivalent to the following:
-	// return func(yield func(K, V) bool) {
-	// 	for k, v := range ByIndex(om, getIndex) {
-	// 		if !yield(k, v.V) {
-	// 			return
-	// 		}
-	// 	}
-	// }
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
 }
@@ -107,3 +107,119 @@ func testByIndex[V, Val any, M interface {
 		t.Fatalf("got: %d, want: %d", i, wantSize)
 	}
 }
+
+func TestByIndex_Ties(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		om := UserDefinedOrderedMap{
+			"e": &ValueWithIndex{},
+			"d": &ValueWithIndex{idx: 2},
+			"c": &ValueWithIndex{idx: 1},
+			"b": &ValueWithIndex{idx: 2},
+			"a": &ValueWithIndex{},
+		}
+
+		for range 10 {
+			if got, want := collectKeys(om), []string{"c", "b", "d", "a", "e"}; !slices.Equal(got, want) {
+				t.Fatalf("got: %v, want: %v", got, want)
+			}
+		}
+
+		reversed := func(a, b string) int { return -less(a, b) }
+		if got, want := collectKeys(byIndexFunc[*ValueWithIndex](ordmap.ByIndexFunc(om, getIndex, reversed))),
+			[]string{"c", "d", "b", "e", "a"}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+
+		if k, _, _ := om.First(); k != "c" {
+			t.Fatalf("got: %v, want: c", k)
+		}
+
+		if k, _, _ := om.Last(); k != "e" {
+			t.Fatalf("got: %v, want: e", k)
+		}
+	})
+
+	t.Run("ordered map", func(t *testing.T) {
+		var om OrderedMap
+		om.Set("c", Value{})
+		om.Set("a", Value{})
+		om["b"] = ordmap.Value[Value]{}
+		om["z"] = ordmap.Value[Value]{}
+		om["x"] = ordmap.Value[Value]{}
+
+		for range 10 {
+			if got, want := collectKeys(om), []string{"c", "a", "b", "x", "z"}; !slices.Equal(got, want) {
+				t.Fatalf("got: %v, want: %v", got, want)
+			}
+		}
+
+		reversed := func(a, b string) int { return -less(a, b) }
+		if got, want := collectKeys(byIndexFunc[Value](om.ByIndexFunc(reversed))),
+			[]string{"c", "a", "z", "x", "b"}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+	})
+
+	t.Run("non-string keys", func(t *testing.T) {
+		ints := ordmap.OrderedMap[int, Value]{}
+		for _, k := range []int{10, 9, -1, 100} {
+			ints[k] = ordmap.Value[Value]{}
+		}
+
+		var got []int
+		for k := range ints.ByIndex() {
+			got = append(got, k)
+		}
+
+		if want := []int{-1, 9, 10, 100}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+
+		type name string
+
+		names := ordmap.OrderedMap[name, Value]{"b": {}, "a": {}, "c": {}}
+
+		var gotNames []name
+		for k := range names.ByIndex() {
+			gotNames = append(gotNames, k)
+		}
+
+		if want := []name{"a", "b", "c"}; !slices.Equal(gotNames, want) {
+			t.Fatalf("got: %v, want: %v", gotNames, want)
+		}
+
+		floats := ordmap.OrderedMap[float64, Value]{2.5: {}, -1: {}, 10: {}}
+
+		var gotFloats []float64
+		for k := range floats.ByIndex() {
+			gotFloats = append(gotFloats, k)
+		}
+
+		if want := []float64{-1, 2.5, 10}; !slices.Equal(gotFloats, want) {
+			t.Fatalf("got: %v, want: %v", gotFloats, want)
+		}
+
+		type point struct{ x, y int }
+
+		points := ordmap.OrderedMap[point, Value]{
+			{2, 1}: {},
+			{1, 2}: {},
+		}
+
+		var gotPoints []point
+		for k := range points.ByIndex() {
+			gotPoints = append(gotPoints, k)
+		}
+
+		if want := []point{{1, 2}, {2, 1}}; !slices.Equal(gotPoints, want) {
+			t.Fatalf("got: %v, want: %v", gotPoints, want)
+		}
+	})
+}
+
+// byIndexFunc turns a sequence into a ByIndexer.
+type byIndexFunc[V any] iter.Seq2[string, V]
+
+func (f byIndexFunc[V]) ByIndex() iter.Seq2[string, V] { return iter.Seq2[string, V](f) }
@@ -0,0 +1,86 @@
+package ordmap
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"iter"
+)
+
+var (
+	_ ByIndexer[string, any] = (*Cache[map[string]any, string, any])(nil)
+	_ json.MarshalerTo       = (*Cache[map[string]any, string, any])(nil)
+)
+
+// Cache remembers the order of the keys of an ordered map so that repeated iterations do not sort them again.
+// Before each use, it checks in linear time that the map still matches the remembered order
+// and only sorts the keys again if it does not.
+// This makes it safe to change the map in any way, e.g. by Set, Sort or UnmarshalJSONFrom, while the cache exists.
+// Maps with uninitialized or duplicate indices are sorted every time.
+type Cache[M ~map[K]V, K comparable, V any] struct {
+	m        *M
+	getIndex func(V) int
+	keys     []K
+}
+
+// Cache returns a cache of the order of the map.
+func (om *OrderedMap[K, V]) Cache() *Cache[OrderedMap[K, V], K, Value[V]] {
+	return NewCache(om, getIndex)
+}
+
+// NewCache is a helper function to create a cache of the order of a map.
+func NewCache[M ~map[K]V, K comparable, V any](m *M, getIndex func(V) int) *Cache[M, K, V] {
+	return &Cache[M, K, V]{m: m, getIndex: getIndex}
+}
+
+// Keys returns the keys of the map sorted by index.
+// The returned slice is shared with the cache and must not be modified.
+func (c *Cache[M, K, V]) Keys() []K {
+	if !c.valid() {
+		c.keys = orderedKeys(*c.m, c.getIndex)
+	}
+
+	return c.keys
+}
+
+// ByIndex returns a sequence of key-value pairs sorted by index.
+func (c *Cache[M, K, V]) ByIndex() iter.Seq2[K, V] {
+	keys := c.Keys()
+
+	return func(yield func(K, V) bool) {
+		for _, k := range keys {
+			if !yield(k, (*c.m)[k]) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSONTo marshals the key-value pairs of the map in order.
+func (c *Cache[M, K, V]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return MarshalJSONTo(c, enc)
+}
+
+// valid reports whether the remembered keys are still the keys of the map in order.
+func (c *Cache[M, K, V]) valid() bool {
+	if c.keys == nil || len(c.keys) != len(*c.m) {
+		return false
+	}
+
+	prev := 0
+	for i, k := range c.keys {
+		v, ok := (*c.m)[k]
+		if !ok {
+			return false
+		}
+
+		// the indices must be initialized and strictly increasing
+		idx := c.getIndex(v)
+		if idx == 0 || (i > 0 && prev >= idx) {
+			return false
+		}
+
+		prev = idx
+	}
+
+	return true
+}
@@ -0,0 +1,96 @@
+package ordmap_test
+
+import (
+	"encoding/json/v2"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		var om UserDefinedOrderedMap
+
+		calls := 0
+		c := ordmap.NewCache(&om, func(v *ValueWithIndex) int { calls++; return v.idx })
+
+		if keys := c.Keys(); len(keys) != 0 {
+			t.Fatalf("got: %v", keys)
+		}
+
+		om.Set("foo", &ValueWithIndex{Foo: "a"})
+		om.Set("bar", &ValueWithIndex{Foo: "b"})
+		om.Set("baz", &ValueWithIndex{Foo: "c"})
+
+		keys := c.Keys()
+		if want := []string{"foo", "bar", "baz"}; !slices.Equal(keys, want) {
+			t.Fatalf("got: %v, want: %v", keys, want)
+		}
+
+		// an unchanged map is not sorted again
+		calls = 0
+		if again := c.Keys(); &again[0] != &keys[0] {
+			t.Fatal("expected the cached keys")
+		} else if calls != len(om) {
+			t.Fatalf("got: %d calls, want: %d", calls, len(om))
+		}
+
+		// changing the map in any way is noticed
+		for _, tc := range []struct {
+			name   string
+			change func()
+			want   []string
+		}{
+			{"set", func() { om.Set("qux", &ValueWithIndex{}) }, []string{"foo", "bar", "baz", "qux"}},
+			{"sort", func() { om.Sort() }, []string{"bar", "baz", "foo", "qux"}},
+			{"delete", func() { delete(om, "baz") }, []string{"bar", "foo", "qux"}},
+			{"replace", func() { delete(om, "qux"); om["moo"] = &ValueWithIndex{idx: 4} }, []string{"bar", "foo", "moo"}},
+			{"change index", func() { om["bar"].idx = 5 }, []string{"foo", "moo", "bar"}},
+			{"unmarshal", func() {
+				if err := json.Unmarshal([]byte(`{"a":{},"b":{}}`), &om); err != nil {
+					t.Fatal(err)
+				}
+			}, []string{"a", "b"}},
+		} {
+			tc.change()
+
+			if got := collectKeys(c); !slices.Equal(got, tc.want) {
+				t.Fatalf("%s: got: %v, want: %v", tc.name, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("ordered map", func(t *testing.T) {
+		var om OrderedMap
+		c := om.Cache()
+
+		om.Set("foo", Value{Foo: "a", Bar: 6})
+		om.Set("bar", Value{Foo: "b", Bar: 7})
+
+		for range c.ByIndex() {
+			break // i.e. yield returns false
+		}
+
+		// uninitialized indices are sorted every time
+		om["baz"] = ordmap.Value[Value]{V: Value{Foo: "c", Bar: 8}}
+
+		got, err := json.Marshal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		const want = `{"foo":{"foo":"a","bar":6},"bar":{"foo":"b","bar":7},"baz":{"foo":"c","bar":8}}`
+		if string(got) != want {
+			t.Fatalf("got: %v, want: %v", string(got), want)
+		}
+
+		om.Set("qux", Value{})
+
+		if got, want := collectKeys(c), []string{"foo", "bar", "qux", "baz"}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+	})
+}
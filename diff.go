@@ -0,0 +1,224 @@
+package ordmap
+
+import (
+	"encoding/json/v2"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ChangeKind describes how a key differs between two ordered maps.
+// A key that changed its value and its position has both Changed and Moved set.
+type ChangeKind int
+
+// Unchanged means the key has the same value in both maps and did not move.
+const Unchanged ChangeKind = 0
+
+const (
+	// Added means the key is only in the new map.
+	Added ChangeKind = 1 << iota
+	// Removed means the key is only in the old map.
+	Removed
+	// Changed means the key has different values in both maps.
+	Changed
+	// Moved means the key changed its position relative to the other keys.
+	Moved
+)
+
+// String returns the names of the kinds of change, e.g. "changed, moved".
+func (k ChangeKind) String() string {
+	if k == Unchanged {
+		return "unchanged"
+	}
+
+	var names []string
+	for _, kind := range []struct {
+		kind ChangeKind
+		name string
+	}{{Added, "added"}, {Removed, "removed"}, {Changed, "changed"}, {Moved, "moved"}} {
+		if k&kind.kind != 0 {
+			names = append(names, kind.name)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// Change is a difference of a key between two ordered maps.
+// The indices are zero-based positions in the order of the respective map and -1 if the key is not in it.
+type Change[K comparable, V any] struct {
+	Kind     ChangeKind
+	Key      K
+	OldIndex int
+	NewIndex int
+	Old      V
+	New      V
+}
+
+// Diff returns the differences between the ordered maps a and b, in the order of a unified diff:
+// removed keys at their old position and all other keys at their new position.
+// Keys that keep their order relative to each other are not reported as moved,
+// so a reordering reports as few keys as possible as moved.
+// If equal is nil, values are compared with reflect.DeepEqual.
+func Diff[K comparable, V any](a, b ByIndexer[K, V], equal func(V, V) bool) []Change[K, V] {
+	var changes []Change[K, V]
+	for _, c := range diffEntries(a, b, equal) {
+		if c.Kind != Unchanged {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+// Unified renders the differences between the ordered maps a and b like a unified diff,
+// with the given number of unchanged keys around each difference as context.
+// Keys and values are encoded as JSON. Each line starts with a marker:
+// " " for unchanged, "-" for removed and "+" for added keys,
+// "-" and "+" for the old and new value of a changed key
+// and "~" for a key that moved, at its new position.
+// If equal is nil, values are compared with reflect.DeepEqual.
+func Unified[K comparable, V any](a, b ByIndexer[K, V], equal func(V, V) bool, context int) string {
+	entries := diffEntries(a, b, equal)
+
+	// decide which entries to show
+	show := make([]bool, len(entries))
+	for i, e := range entries {
+		if e.Kind == Unchanged {
+			continue
+		}
+
+		for j := max(0, i-context); j <= min(len(entries)-1, i+context); j++ {
+			show[j] = true
+		}
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString("--- a\n+++ b\n")
+
+	for i, e := range entries {
+		if !show[i] {
+			continue
+		}
+
+		// separate groups of differences that are too far apart
+		if i == 0 || !show[i-1] {
+			sb.WriteString("@@\n")
+		}
+
+		key := formatJSON(e.Key)
+
+		switch {
+		case e.Kind == Unchanged:
+			fmt.Fprintf(sb, " %s: %s\n", key, formatJSON(e.New))
+		case e.Kind == Added:
+			fmt.Fprintf(sb, "+%s: %s\n", key, formatJSON(e.New))
+		case e.Kind == Removed:
+			fmt.Fprintf(sb, "-%s: %s\n", key, formatJSON(e.Old))
+		case e.Kind&Moved != 0:
+			if e.Kind&Changed != 0 {
+				fmt.Fprintf(sb, "-%s: %s\n", key, formatJSON(e.Old))
+			}
+
+			fmt.Fprintf(sb, "~%s: %s (moved from %d to %d)\n", key, formatJSON(e.New), e.OldIndex, e.NewIndex)
+		default: // changed
+			fmt.Fprintf(sb, "-%s: %s\n+%s: %s\n", key, formatJSON(e.Old), key, formatJSON(e.New))
+		}
+	}
+
+	return sb.String()
+}
+
+// diffEntries returns an entry for every key of a and b in the order of a unified diff,
+// including unchanged keys.
+func diffEntries[K comparable, V any](a, b ByIndexer[K, V], equal func(V, V) bool) []Change[K, V] {
+	if equal == nil {
+		equal = func(x, y V) bool { return reflect.DeepEqual(x, y) }
+	}
+
+	aKeys, aVals := collectEntries(a)
+	bKeys, bVals := collectEntries(b)
+
+	oldIndex := make(map[K]int, len(aKeys))
+	for i, k := range aKeys {
+		oldIndex[k] = i
+	}
+
+	newIndex := make(map[K]int, len(bKeys))
+	for j, k := range bKeys {
+		newIndex[k] = j
+	}
+
+	entry := func(k K, moved bool) Change[K, V] {
+		c := Change[K, V]{Key: k, OldIndex: -1, NewIndex: -1}
+		i, inA := oldIndex[k]
+		j, inB := newIndex[k]
+
+		switch {
+		case !inA:
+			c.Kind, c.NewIndex, c.New = Added, j, bVals[j]
+		case !inB:
+			c.Kind, c.OldIndex, c.Old = Removed, i, aVals[i]
+		default:
+			c.OldIndex, c.NewIndex, c.Old, c.New = i, j, aVals[i], bVals[j]
+			if !equal(c.Old, c.New) {
+				c.Kind |= Changed
+			}
+
+			if moved {
+				c.Kind |= Moved
+			}
+		}
+
+		return c
+	}
+
+	var entries []Change[K, V]
+
+	i, j := 0, 0
+	flush := func(untilI, untilJ int) {
+		// removed keys at their old position, keys that moved away are reported at their new position
+		for ; i < untilI; i++ {
+			if _, inB := newIndex[aKeys[i]]; !inB {
+				entries = append(entries, entry(aKeys[i], false))
+			}
+		}
+
+		for ; j < untilJ; j++ {
+			_, inA := oldIndex[bKeys[j]]
+			entries = append(entries, entry(bKeys[j], inA))
+		}
+	}
+
+	for _, pair := range lcs(aKeys, bKeys) {
+		flush(pair[0], pair[1])
+		entries = append(entries, entry(aKeys[pair[0]], false))
+		i, j = pair[0]+1, pair[1]+1
+	}
+
+	flush(len(aKeys), len(bKeys))
+
+	return entries
+}
+
+// collectEntries returns the keys and values in order.
+func collectEntries[K comparable, V any](m ByIndexer[K, V]) ([]K, []V) {
+	var keys []K
+	var vals []V
+	for k, v := range m.ByIndex() {
+		keys = append(keys, k)
+		vals = append(vals, v)
+	}
+
+	return keys, vals
+}
+
+// formatJSON returns the value encoded as JSON or formatted by fmt if it cannot be encoded.
+func formatJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,144 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func newMap(kv ...any) ordmap.OrderedMap[string, int] {
+	om := ordmap.OrderedMap[string, int]{}
+	for i := 0; i < len(kv); i += 2 {
+		om.Set(kv[i].(string), kv[i+1].(int))
+	}
+
+	return om
+}
+
+// largeMaps returns a map with many keys and a copy in which every fiftieth key is moved to the front.
+func largeMaps() (large, moved ordmap.OrderedMap[string, int]) {
+	for i := range 5_000 {
+		large.Set(fmt.Sprint(i), i)
+
+		if i%50 == 0 {
+			moved.Set(fmt.Sprint(i), i)
+		}
+	}
+
+	for k, v := range large.ByIndex() {
+		moved.Set(k, v)
+	}
+
+	return large, moved
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	a := newMap("a", 1, "b", 2, "c", 3, "d", 4)
+	b := newMap("a", 1, "c", 30, "x", 5, "d", 4, "b", 2)
+
+	got := ordmap.Diff(a, b, nil)
+	want := []ordmap.Change[string, int]{
+		{Kind: ordmap.Changed, Key: "c", OldIndex: 2, NewIndex: 1, Old: 3, New: 30},
+		{Kind: ordmap.Added, Key: "x", OldIndex: -1, NewIndex: 2, New: 5},
+		{Kind: ordmap.Moved, Key: "b", OldIndex: 1, NewIndex: 4, Old: 2, New: 2},
+	}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("got: %+v, want: %+v", got, want)
+	}
+
+	t.Run("pure reorder", func(t *testing.T) {
+		got := ordmap.Diff(newMap("a", 1, "b", 2, "c", 3, "d", 4), newMap("d", 4, "a", 1, "b", 2, "c", 3),
+			func(x, y int) bool { return x == y })
+		want := []ordmap.Change[string, int]{
+			{Kind: ordmap.Moved, Key: "d", OldIndex: 3, NewIndex: 0, Old: 4, New: 4},
+		}
+
+		if !slices.Equal(got, want) {
+			t.Fatalf("got: %+v, want: %+v", got, want)
+		}
+	})
+
+	t.Run("removed and moved and changed", func(t *testing.T) {
+		got := ordmap.Diff(newMap("a", 1, "b", 2, "c", 3), newMap("c", 3, "a", 10), nil)
+		want := []ordmap.Change[string, int]{
+			{Kind: ordmap.Removed, Key: "b", OldIndex: 1, NewIndex: -1, Old: 2},
+			{Kind: ordmap.Changed | ordmap.Moved, Key: "a", OldIndex: 0, NewIndex: 1, Old: 1, New: 10},
+		}
+
+		if !slices.Equal(got, want) {
+			t.Fatalf("got: %+v, want: %+v", got, want)
+		}
+
+		if got := (ordmap.Changed | ordmap.Moved).String(); got != "changed, moved" {
+			t.Fatalf("got: %q", got)
+		}
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		if got := ordmap.Diff(a, a, nil); len(got) != 0 {
+			t.Fatalf("got: %+v", got)
+		}
+	})
+
+	t.Run("custom equality", func(t *testing.T) {
+		got := ordmap.Diff(newMap("a", 1), newMap("a", -1), func(x, y int) bool { return x*x == y*y })
+		if len(got) != 0 {
+			t.Fatalf("got: %+v", got)
+		}
+	})
+
+	t.Run("large", func(t *testing.T) {
+		large, moved := largeMaps()
+
+		got := ordmap.Diff(large, moved, nil)
+		if len(got) != 99 {
+			t.Fatalf("got %d changes, want 99", len(got))
+		}
+
+		for _, c := range got {
+			if c.Kind != ordmap.Moved || c.OldIndex%50 != 0 {
+				t.Fatalf("got: %+v", c)
+			}
+		}
+	})
+}
+
+func TestUnified(t *testing.T) {
+	t.Parallel()
+
+	a := newMap("a", 1, "b", 2, "c", 3, "d", 4, "e", 5, "f", 6, "g", 7)
+	b := newMap("b", 2, "c", 3, "d", 4, "e", 5, "f", 60, "g", 7, "a", 1, "h", 8)
+
+	const want = `--- a
++++ b
+@@
+ "e": 5
+-"f": 6
++"f": 60
+ "g": 7
+~"a": 1 (moved from 0 to 6)
++"h": 8
+`
+
+	if got := ordmap.Unified(a, b, nil, 1); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	const wantNoContext = `--- a
++++ b
+@@
+-"b": 2
+@@
++"x": 1
+`
+
+	got := ordmap.Unified(newMap("a", 0, "b", 2, "c", 0), newMap("a", 0, "c", 0, "x", 1), nil, 0)
+	if got != wantNoContext {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, wantNoContext)
+	}
+}
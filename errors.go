@@ -0,0 +1,43 @@
+package ordmap
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MarkRosemaker/errpath"
+)
+
+// ErrKeyNotFound signals that a key is not present in the map.
+var ErrKeyNotFound = errors.New("key not found")
+
+// ErrKeyExists signals that a key is already present in the map.
+var ErrKeyExists = errors.New("key already exists")
+
+// ErrDuplicateKey signals that a key appears more than once in a JSON object.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// ErrOutOfRange signals that a position is outside of the order of the map.
+var ErrOutOfRange = errors.New("position out of range")
+
+// errKeyNotFound returns an error for a key that is not present in the map.
+func errKeyNotFound[K comparable](key K) error {
+	return &errpath.ErrKey{Key: fmt.Sprint(key), Err: ErrKeyNotFound}
+}
+
+// ErrInvalidPair signals that a key-value pair in a JSON array does not consist of exactly a key and a value.
+var ErrInvalidPair = errors.New("pair must have exactly two elements")
+
+// ErrInvalidPointer signals that a JSON pointer is not valid or cannot be used for the operation.
+var ErrInvalidPointer = errors.New("invalid JSON pointer")
+
+// ErrInvalidIndex signals that a JSON pointer token is not a valid array index.
+var ErrInvalidIndex = errors.New("invalid array index")
+
+// ErrNotContainer signals that a JSON pointer refers into a value that is neither an object nor an array.
+var ErrNotContainer = errors.New("value is neither an object nor an array")
+
+// ErrTestFailed signals that the value of a JSON Patch "test" operation does not match the document.
+var ErrTestFailed = errors.New("test failed")
+
+// ErrConflict signals that two sides of a merge changed the same key in different ways.
+var ErrConflict = errors.New("conflicting changes")
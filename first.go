@@ -0,0 +1,48 @@
+package ordmap
+
+// First returns the first key-value pair by index and whether the map has any entries.
+func (om OrderedMap[K, V]) First() (K, V, bool) {
+	k, v, ok := First(om, getIndex)
+	return k, v.V, ok
+}
+
+// Last returns the last key-value pair by index and whether the map has any entries.
+func (om OrderedMap[K, V]) Last() (K, V, bool) {
+	k, v, ok := Last(om, getIndex)
+	return k, v.V, ok
+}
+
+// First is a helper function to get the first key-value pair of a map by index.
+func First[M ~map[K]V, K comparable, V any](m M, getIndex func(V) int) (K, V, bool) {
+	return find(m, getIndex, func(c int) bool { return c < 0 })
+}
+
+// Last is a helper function to get the last key-value pair of a map by index.
+func Last[M ~map[K]V, K comparable, V any](m M, getIndex func(V) int) (K, V, bool) {
+	return find(m, getIndex, func(c int) bool { return c > 0 })
+}
+
+// find returns the key-value pair that is preferred over all others in the order given by ByIndex.
+func find[M ~map[K]V, K comparable, V any](
+	m M, getIndex func(V) int, prefer func(int) bool,
+) (key K, val V, found bool) {
+	compare := compareKeysFunc[K]()
+
+	for k, v := range m {
+		if !found {
+			key, val, found = k, v, true
+			continue
+		}
+
+		c := compareIndex(getIndex(v), getIndex(val))
+		if c == 0 {
+			c = compare(k, key)
+		}
+
+		if prefer(c) {
+			key, val = k, v
+		}
+	}
+
+	return key, val, found
+}
@@ -0,0 +1,78 @@
+package ordmap_test
+
+import (
+	"testing"
+
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func (om UserDefinedOrderedMap) First() (string, *ValueWithIndex, bool) {
+	return ordmap.First(om, getIndex)
+}
+
+func (om UserDefinedOrderedMap) Last() (string, *ValueWithIndex, bool) {
+	return ordmap.Last(om, getIndex)
+}
+
+func TestFirstLast(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		var om UserDefinedOrderedMap
+		if k, v, ok := om.First(); ok || k != "" || v != nil {
+			t.Fatalf("got: %v, %v, %t", k, v, ok)
+		}
+
+		if k, v, ok := om.Last(); ok || k != "" || v != nil {
+			t.Fatalf("got: %v, %v, %t", k, v, ok)
+		}
+
+		om = UserDefinedOrderedMap{
+			"foo": &ValueWithIndex{Foo: "a", idx: 5},
+			"bar": &ValueWithIndex{Foo: "b", idx: 2},
+			"baz": &ValueWithIndex{Foo: "c", idx: 9},
+		}
+
+		if k, v, ok := om.First(); !ok || k != "bar" || v.Foo != "b" {
+			t.Fatalf("got: %v, %v, %t", k, v, ok)
+		}
+
+		if k, v, ok := om.Last(); !ok || k != "baz" || v.Foo != "c" {
+			t.Fatalf("got: %v, %v, %t", k, v, ok)
+		}
+
+		// an uninitialized entry comes last
+		om["qux"] = &ValueWithIndex{Foo: "d"}
+
+		if k, _, _ := om.First(); k != "bar" {
+			t.Fatalf("got: %v, want: bar", k)
+		}
+
+		if k, _, _ := om.Last(); k != "qux" {
+			t.Fatalf("got: %v, want: qux", k)
+		}
+	})
+
+	t.Run("ordered map", func(t *testing.T) {
+		var om OrderedMap
+		if _, _, ok := om.First(); ok {
+			t.Fatal("expected no entry")
+		}
+
+		if _, _, ok := om.Last(); ok {
+			t.Fatal("expected no entry")
+		}
+
+		om.Set("foo", Value{Foo: "a"})
+		om.Set("bar", Value{Foo: "b"})
+		om.Set("baz", Value{Foo: "c"})
+
+		if k, v, ok := om.First(); !ok || k != "foo" || v.Foo != "a" {
+			t.Fatalf("got: %v, %v, %t", k, v, ok)
+		}
+
+		if k, v, ok := om.Last(); !ok || k != "baz" || v.Foo != "c" {
+			t.Fatalf("got: %v, %v, %t", k, v, ok)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package ordmap
+
+// Get returns the value for the key and whether the key is present in the map.
+func (om OrderedMap[K, V]) Get(key K) (V, bool) {
+	v, ok := Get(om, key)
+	return v.V, ok
+}
+
+// Get is a helper function to get the value for the key and whether the key is present in the map.
+func Get[M ~map[K]V, K comparable, V any](m M, key K) (V, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+// Has reports whether the key is present in the map.
+func (om OrderedMap[K, V]) Has(key K) bool { return Has(om, key) }
+
+// Has is a helper function to report whether the key is present in the map.
+func Has[M ~map[K]V, K comparable, V any](m M, key K) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// Len returns the number of entries in the map.
+func (om OrderedMap[K, V]) Len() int { return Len(om) }
+
+// Len is a helper function to return the number of entries in the map.
+func Len[M ~map[K]V, K comparable, V any](m M) int { return len(m) }
+
+// Delete removes the key from the map.
+// The indices of the remaining entries are left untouched, so their order is kept.
+func (om OrderedMap[K, V]) Delete(key K) { Delete(om, key) }
+
+// Delete is a helper function to remove the key from the map.
+// The indices of the remaining entries are left untouched, so their order is kept.
+func Delete[M ~map[K]V, K comparable, V any](m M, key K) { delete(m, key) }
@@ -0,0 +1,110 @@
+package ordmap_test
+
+import (
+	"testing"
+
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func (om UserDefinedOrderedMap) Get(key string) (*ValueWithIndex, bool) { return ordmap.Get(om, key) }
+
+func (om UserDefinedOrderedMap) Has(key string) bool { return ordmap.Has(om, key) }
+
+func (om UserDefinedOrderedMap) Len() int { return ordmap.Len(om) }
+
+func (om UserDefinedOrderedMap) Delete(key string) { ordmap.Delete(om, key) }
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	var om OrderedMapPointer
+	if om.Len() != 0 {
+		t.Fatalf("got: %d, want: 0", om.Len())
+	}
+
+	if om.Has("foo") {
+		t.Fatal("expected foo to be missing")
+	}
+
+	if v, ok := om.Get("foo"); ok || v != nil {
+		t.Fatalf("got: %v, %t", v, ok)
+	}
+
+	om.Delete("foo") // no panic
+
+	om.Set("foo", &Value{Foo: "a"})
+	om.Set("bar", &Value{Foo: "b"})
+	om.Set("baz", &Value{Foo: "c"})
+
+	if om.Len() != 3 {
+		t.Fatalf("got: %d, want: 3", om.Len())
+	}
+
+	if !om.Has("bar") {
+		t.Fatal("expected bar to be present")
+	}
+
+	if v, ok := om.Get("bar"); !ok || v.Foo != "b" {
+		t.Fatalf("got: %v, %t", v, ok)
+	}
+
+	om.Delete("bar")
+
+	if om.Has("bar") {
+		t.Fatal("expected bar to be deleted")
+	}
+
+	if om.Len() != 2 {
+		t.Fatalf("got: %d, want: 2", om.Len())
+	}
+
+	keys := []string{"foo", "baz"}
+
+	i := 0
+	for k := range om.ByIndex() {
+		if k != keys[i] {
+			t.Fatalf("got: %v, want: %v", k, keys[i])
+		}
+
+		i++
+	}
+
+	// a new key still comes after the remaining ones
+	om.Set("qux", &Value{Foo: "d"})
+	if k, _, _ := om.Last(); k != "qux" {
+		t.Fatalf("got: %v, want: qux", k)
+	}
+}
+
+func TestGet_UserDefined(t *testing.T) {
+	t.Parallel()
+
+	var om UserDefinedOrderedMap
+	if om.Len() != 0 || om.Has("foo") {
+		t.Fatal("expected an empty map")
+	}
+
+	if v, ok := om.Get("foo"); ok || v != nil {
+		t.Fatalf("got: %v, %t", v, ok)
+	}
+
+	om.Delete("foo") // no panic
+
+	om.Set("foo", &ValueWithIndex{Foo: "a"})
+	om.Set("bar", &ValueWithIndex{Foo: "b"})
+	om.Set("baz", &ValueWithIndex{Foo: "c"})
+
+	if v, ok := om.Get("bar"); !ok || v.Foo != "b" {
+		t.Fatalf("got: %v, %t", v, ok)
+	}
+
+	om.Delete("bar")
+
+	if om.Has("bar") || om.Len() != 2 {
+		t.Fatal("expected bar to be deleted")
+	}
+
+	if got := collectKeys(om); got[0] != "foo" || got[1] != "baz" {
+		t.Fatalf("got: %v", got)
+	}
+}
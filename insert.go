@@ -0,0 +1,106 @@
+package ordmap
+
+import (
+	"slices"
+
+	"github.com/MarkRosemaker/errpath"
+)
+
+// InsertAt sets a value in the map so that the key is at the given zero-based position of the order.
+// If the key already exists, it is moved to that position.
+func (om *OrderedMap[K, V]) InsertAt(pos int, key K, v V) error {
+	return InsertAt(om, pos, key, Value[V]{V: v}, getIndex[V], setIndex[V])
+}
+
+// InsertBefore sets a value in the map so that the key comes directly before the mark.
+// If the key already exists, it is moved to that position.
+func (om *OrderedMap[K, V]) InsertBefore(key K, v V, mark K) error {
+	return InsertBefore(om, key, Value[V]{V: v}, mark, getIndex[V], setIndex[V])
+}
+
+// InsertAfter sets a value in the map so that the key comes directly after the mark.
+// If the key already exists, it is moved to that position.
+func (om *OrderedMap[K, V]) InsertAfter(key K, v V, mark K) error {
+	return InsertAfter(om, key, Value[V]{V: v}, mark, getIndex[V], setIndex[V])
+}
+
+// InsertAt is a helper function to set a value in the map at the given zero-based position of the order
+// and set the indices accordingly.
+func InsertAt[M ~map[K]V, K comparable, V any](
+	m *M, pos int, key K, v V,
+	getIndex func(V) int,
+	setIndex func(V, int) V,
+) error {
+	return insert(m, key, v, getIndex, setIndex, func(keys []K, _ int) (int, error) {
+		if pos < 0 || pos > len(keys) {
+			return 0, &errpath.ErrIndex{Index: pos, Err: ErrOutOfRange}
+		}
+
+		return pos, nil
+	})
+}
+
+// InsertBefore is a helper function to set a value in the map directly before the mark
+// and set the indices accordingly.
+func InsertBefore[M ~map[K]V, K comparable, V any](
+	m *M, key K, v V, mark K,
+	getIndex func(V) int,
+	setIndex func(V, int) V,
+) error {
+	return insert(m, key, v, getIndex, setIndex, func(keys []K, pos int) (int, error) {
+		if mark == key && pos != -1 {
+			return pos, nil
+		}
+
+		return position(keys, mark)
+	})
+}
+
+// InsertAfter is a helper function to set a value in the map directly after the mark
+// and set the indices accordingly.
+func InsertAfter[M ~map[K]V, K comparable, V any](
+	m *M, key K, v V, mark K,
+	getIndex func(V) int,
+	setIndex func(V, int) V,
+) error {
+	return insert(m, key, v, getIndex, setIndex, func(keys []K, pos int) (int, error) {
+		if mark == key && pos != -1 {
+			return pos, nil
+		}
+
+		pos, err := position(keys, mark)
+		return pos + 1, err
+	})
+}
+
+// insert takes the key out of the order if it exists and inserts it with the new value
+// at the position that target returns for the remaining keys and the previous position of the key,
+// which is -1 for a new key.
+func insert[M ~map[K]V, K comparable, V any](
+	m *M, key K, v V,
+	getIndex func(V) int,
+	setIndex func(V, int) V,
+	target func([]K, int) (int, error),
+) error {
+	keys := orderedKeys(*m, getIndex)
+
+	pos := slices.Index(keys, key)
+	if pos != -1 {
+		keys = slices.Delete(keys, pos, pos+1)
+	}
+
+	pos, err := target(keys, pos)
+	if err != nil {
+		return err
+	}
+
+	// check if the map is nil and create it if it is
+	if *m == nil {
+		*m = M{}
+	}
+
+	(*m)[key] = v
+	setIndices(*m, slices.Insert(keys, pos, key), setIndex)
+
+	return nil
+}
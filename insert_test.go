@@ -0,0 +1,116 @@
+package ordmap_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func (om *UserDefinedOrderedMap) InsertAt(pos int, key string, v *ValueWithIndex) error {
+	return ordmap.InsertAt(om, pos, key, v, getIndex, setIndex)
+}
+
+func (om *UserDefinedOrderedMap) InsertBefore(key string, v *ValueWithIndex, mark string) error {
+	return ordmap.InsertBefore(om, key, v, mark, getIndex, setIndex)
+}
+
+func (om *UserDefinedOrderedMap) InsertAfter(key string, v *ValueWithIndex, mark string) error {
+	return ordmap.InsertAfter(om, key, v, mark, getIndex, setIndex)
+}
+
+type inserter[V any] interface {
+	ordmap.ByIndexer[string, V]
+	InsertAt(pos int, key string, v V) error
+	InsertBefore(key string, v V, mark string) error
+	InsertAfter(key string, v V, mark string) error
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		var om UserDefinedOrderedMap
+		testInsert(t, &om, func(s string) *ValueWithIndex { return &ValueWithIndex{Foo: s} },
+			func(v *ValueWithIndex) string { return v.Foo })
+
+		// the indices are consistent
+		for i, k := range []string{"e", "a", "c", "d", "b"} {
+			if got := om[k].idx; got != i+1 {
+				t.Fatalf("index of %s: got: %d, want: %d", k, got, i+1)
+			}
+		}
+	})
+
+	t.Run("ordered map", func(t *testing.T) {
+		var om OrderedMap
+		testInsert(t, &om, func(s string) Value { return Value{Foo: s} },
+			func(v Value) string { return v.Foo })
+	})
+}
+
+func testInsert[V any](t *testing.T, om inserter[V], val func(string) V, foo func(V) string) {
+	t.Helper()
+
+	// errors before the map exists
+	if err := om.InsertAt(1, "a", val("a")); !errors.Is(err, ordmap.ErrOutOfRange) {
+		t.Fatalf("got: %v, want: %v", err, ordmap.ErrOutOfRange)
+	}
+
+	if err := om.InsertBefore("a", val("a"), "a"); !errors.Is(err, ordmap.ErrKeyNotFound) {
+		t.Fatalf("got: %v, want: %v", err, ordmap.ErrKeyNotFound)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		insert func() error
+		want   []string
+	}{
+		{"insert into empty map", func() error { return om.InsertAt(0, "a", val("a")) }, []string{"a"}},
+		{"insert at end", func() error { return om.InsertAt(1, "b", val("b")) }, []string{"a", "b"}},
+		{"insert in between", func() error { return om.InsertAt(1, "c", val("c")) }, []string{"a", "c", "b"}},
+		{"insert before", func() error { return om.InsertBefore("d", val("d"), "b") }, []string{"a", "c", "d", "b"}},
+		{"insert after", func() error { return om.InsertAfter("e", val("e"), "b") }, []string{"a", "c", "d", "b", "e"}},
+		{"insert at front", func() error { return om.InsertAt(0, "e", val("E")) }, []string{"e", "a", "c", "d", "b"}},
+		{"insert before itself", func() error { return om.InsertBefore("e", val("e"), "e") }, []string{"e", "a", "c", "d", "b"}},
+		{"insert after itself", func() error { return om.InsertAfter("a", val("a"), "a") }, []string{"e", "a", "c", "d", "b"}},
+	} {
+		if err := tc.insert(); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		if got := collectKeys(om); !slices.Equal(got, tc.want) {
+			t.Fatalf("%s: got: %v, want: %v", tc.name, got, tc.want)
+		}
+	}
+
+	for k, v := range om.ByIndex() {
+		if foo(v) != k {
+			t.Fatalf("got: %v, want: %v", foo(v), k)
+		}
+	}
+
+	errIdx := errAs[errpath.ErrIndex](t, om.InsertAt(-1, "f", val("f")))
+	if errIdx.Index != -1 {
+		t.Fatalf("got: %d, want: -1", errIdx.Index)
+	}
+
+	// an existing key is taken out of the order first
+	errIdx = errAs[errpath.ErrIndex](t, om.InsertAt(5, "a", val("a")))
+	if errIdx.Index != 5 {
+		t.Fatalf("got: %d, want: 5", errIdx.Index)
+	} else if !errors.Is(errIdx, ordmap.ErrOutOfRange) {
+		t.Fatalf("got: %v, want: %v", errIdx, ordmap.ErrOutOfRange)
+	}
+
+	errKey := errAs[errpath.ErrKey](t, om.InsertAfter("f", val("f"), "x"))
+	if errKey.Key != "x" {
+		t.Fatalf("got: %v, want: x", errKey.Key)
+	}
+
+	if got, want := collectKeys(om), []string{"e", "a", "c", "d", "b"}; !slices.Equal(got, want) {
+		t.Fatalf("got: %v, want: %v", got, want)
+	}
+}
@@ -0,0 +1,145 @@
+package ordmap
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+
+	"github.com/MarkRosemaker/errpath"
+)
+
+// MarshalJSONArrayTo marshals the values in order as a JSON array, e.g. for values that contain their key.
+// To use it when marshalling, register it with json.WithMarshalers.
+func (om *OrderedMap[K, V]) MarshalJSONArrayTo(enc *jsontext.Encoder) error {
+	return MarshalJSONArrayTo(om, enc)
+}
+
+// UnmarshalJSONArrayFrom unmarshals a JSON array into the map, keyed by the key of each value,
+// and sets the indices in the order of the array.
+func (om *OrderedMap[K, V]) UnmarshalJSONArrayFrom(dec *jsontext.Decoder, keyOf func(V) K) error {
+	return UnmarshalJSONArrayFrom(om, dec, func(v Value[V]) K { return keyOf(v.V) }, setIndex)
+}
+
+// UnmarshalJSONArrayFromField unmarshals a JSON array of objects into the map, keyed by the given member
+// of each object, and sets the indices in the order of the array.
+func (om *OrderedMap[K, V]) UnmarshalJSONArrayFromField(dec *jsontext.Decoder, field string) error {
+	return UnmarshalJSONArrayFromField(om, dec, field, setIndex)
+}
+
+// MarshalJSONArrayTo marshals an ordered map as a JSON array of its values in order, leaving out the keys.
+// Like json v2 does for Go maps, it encodes a nil map as JSON null if json.FormatNilMapAsNull is set.
+func MarshalJSONArrayTo[M ByIndexer[K, V], K comparable, V any](
+	m M, enc *jsontext.Encoder,
+) error {
+	return marshalArray(m, enc, func(_ K, v V) error {
+		return json.MarshalEncode(enc, v, enc.Options())
+	})
+}
+
+// UnmarshalJSONArrayFrom is a helper function to unmarshal a JSON array into an ordered map,
+// keyed by what keyOf returns for each value, setting the indices in the order of the array.
+// A key that appears more than once is reported as ErrDuplicateKey.
+func UnmarshalJSONArrayFrom[M ~map[K]R, K comparable, R any](
+	m *M, dec *jsontext.Decoder,
+	keyOf func(R) K,
+	setIndex func(R, int) R,
+) error {
+	return unmarshalArray(m, dec, setIndex, func() (key K, v R, err error) {
+		if err := json.UnmarshalDecode(dec, &v, dec.Options()); err != nil {
+			return key, v, err
+		}
+
+		key = keyOf(v)
+
+		return key, v, checkDuplicate(*m, key)
+	})
+}
+
+// UnmarshalJSONArrayFromField is a helper function to unmarshal a JSON array of objects into an ordered map,
+// keyed by the given member of each object, setting the indices in the order of the array.
+// The member is decoded into the key following the same rules as any other value, so the object must have it.
+// A key that appears more than once is reported as ErrDuplicateKey.
+func UnmarshalJSONArrayFromField[M ~map[K]R, K comparable, R any](
+	m *M, dec *jsontext.Decoder,
+	field string,
+	setIndex func(R, int) R,
+) error {
+	return unmarshalArray(m, dec, setIndex, func() (key K, v R, err error) {
+		if kind := dec.PeekKind(); kind != '{' {
+			return key, v, &json.SemanticError{JSONKind: kind}
+		}
+
+		obj, err := dec.ReadValue()
+		if err != nil {
+			return key, v, err
+		}
+
+		if key, err = keyFromField[K](obj, field, dec.Options()); err != nil {
+			return key, v, locateError(err, dec, obj)
+		}
+
+		if err := checkDuplicate(*m, key); err != nil {
+			return key, v, err
+		}
+
+		if err := json.Unmarshal(obj, &v, dec.Options()); err != nil {
+			return key, v, locateError(err, dec, obj)
+		}
+
+		return key, v, nil
+	})
+}
+
+// locateError makes the byte offset and JSON pointer of a semantic error from decoding the value
+// that was just read from the decoder relative to the whole input instead of the value.
+func locateError(err error, dec *jsontext.Decoder, val jsontext.Value) error {
+	var semErr *json.SemanticError
+	if errors.As(err, &semErr) {
+		semErr.ByteOffset += dec.InputOffset() - int64(len(val))
+		semErr.JSONPointer = dec.StackPointer() + semErr.JSONPointer
+	}
+
+	return err
+}
+
+// keyFromField decodes the key from the member of the JSON object with the given name.
+func keyFromField[K comparable](obj jsontext.Value, field string, opts json.Options) (key K, err error) {
+	dec := jsontext.NewDecoder(bytes.NewReader(obj))
+	if _, err := dec.ReadToken(); err != nil {
+		return key, err // consume '{', should not fail
+	}
+
+	for dec.PeekKind() != '}' {
+		name, err := dec.ReadToken()
+		if err != nil {
+			return key, err
+		}
+
+		if name.String() != field {
+			if err := dec.SkipValue(); err != nil {
+				return key, err
+			}
+
+			continue
+		}
+
+		if err := json.UnmarshalDecode(dec, &key, opts); err != nil {
+			return key, &errpath.ErrField{Field: field, Err: err}
+		}
+
+		return key, nil
+	}
+
+	return key, &errpath.ErrField{Field: field, Err: &errpath.ErrRequired{}}
+}
+
+// checkDuplicate reports ErrDuplicateKey if the key is already present in the map.
+func checkDuplicate[M ~map[K]R, K comparable, R any](m M, key K) error {
+	if _, exists := m[key]; exists {
+		return &errpath.ErrKey{Key: fmt.Sprint(key), Err: ErrDuplicateKey}
+	}
+
+	return nil
+}
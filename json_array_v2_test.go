@@ -0,0 +1,147 @@
+package ordmap_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+type user struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func newDecoder(data string) *jsontext.Decoder {
+	return jsontext.NewDecoder(strings.NewReader(data))
+}
+
+func TestUnmarshalJSONArrayFrom(t *testing.T) {
+	t.Parallel()
+
+	const data = `[{"name":"b","age":2},{"name":"a","age":1},{"age":3,"name":"c"}]`
+
+	t.Run("field", func(t *testing.T) {
+		var om ordmap.OrderedMap[string, user]
+		if err := om.UnmarshalJSONArrayFromField(newDecoder(data), "name"); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := collectKeys(om); !slices.Equal(got, []string{"b", "a", "c"}) {
+			t.Fatalf("got: %v", got)
+		}
+
+		if u, _ := om.Get("c"); u.Age != 3 {
+			t.Fatalf("got: %v", u)
+		}
+
+		got, err := json.Marshal(&om, json.WithMarshalers(json.MarshalToFunc(
+			func(enc *jsontext.Encoder, om *ordmap.OrderedMap[string, user]) error {
+				return om.MarshalJSONArrayTo(enc)
+			})))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `[{"name":"b","age":2},{"name":"a","age":1},{"name":"c","age":3}]`; string(got) != want {
+			t.Fatalf("got: %s, want: %s", got, want)
+		}
+	})
+
+	t.Run("key function", func(t *testing.T) {
+		var om ordmap.OrderedMap[string, user]
+		if err := json.Unmarshal([]byte(data), &om, json.WithUnmarshalers(json.UnmarshalFromFunc(
+			func(dec *jsontext.Decoder, om *ordmap.OrderedMap[string, user]) error {
+				return om.UnmarshalJSONArrayFrom(dec, func(u user) string { return u.Name })
+			}))); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := collectKeys(om); !slices.Equal(got, []string{"b", "a", "c"}) {
+			t.Fatalf("got: %v", got)
+		}
+	})
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		var om UserDefinedOrderedMap
+		if err := ordmap.UnmarshalJSONArrayFromField(&om,
+			newDecoder(`[{"foo":"x","bar":1},{"foo":"y","bar":2}]`), "foo", setIndex,
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		if om["x"].Bar != 1 || om["y"].Bar != 2 || getIndex(om["x"]) != 1 || getIndex(om["y"]) != 2 {
+			t.Fatalf("got: %v", om)
+		}
+	})
+
+	t.Run("duplicate key", func(t *testing.T) {
+		var om ordmap.OrderedMap[string, user]
+
+		err := om.UnmarshalJSONArrayFromField(
+			newDecoder(`[{"name":"a"},{"name":"b"},{"name":"a"}]`), "name")
+		if !errors.Is(err, ordmap.ErrDuplicateKey) {
+			t.Fatalf("got: %v", err)
+		}
+
+		if errIdx := errAs[errpath.ErrIndex](t, err); errIdx.Index != 2 {
+			t.Fatalf("got index %d, want 2", errIdx.Index)
+		}
+
+		if errKey := errAs[errpath.ErrKey](t, err); errKey.Key != "a" {
+			t.Fatalf("got key %q, want a", errKey.Key)
+		}
+
+		err = om.UnmarshalJSONArrayFrom(newDecoder(`[{"name":"a"},{"name":"a"}]`),
+			func(u user) string { return u.Name })
+		if !errors.Is(err, ordmap.ErrDuplicateKey) {
+			t.Fatalf("got: %v", err)
+		}
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		var om ordmap.OrderedMap[string, user]
+
+		err := om.UnmarshalJSONArrayFromField(newDecoder(`[{"name":"a"},{"age":2}]`), "name")
+		errAs[errpath.ErrRequired](t, err)
+
+		if errIdx := errAs[errpath.ErrIndex](t, err); errIdx.Index != 1 {
+			t.Fatalf("got index %d, want 1", errIdx.Index)
+		}
+
+		if errField := errAs[errpath.ErrField](t, err); errField.Field != "name" {
+			t.Fatalf("got field %q, want name", errField.Field)
+		}
+
+		if want := `[1].name is required`; err.Error() != want {
+			t.Fatalf("got: %v, want: %v", err, want)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var om ordmap.OrderedMap[string, user]
+
+		errAs[json.SemanticError](t, om.UnmarshalJSONArrayFromField(newDecoder(`{}`), "name"))
+		errAs[json.SemanticError](t, om.UnmarshalJSONArrayFromField(newDecoder(`["a"]`), "name"))
+
+		err := om.UnmarshalJSONArrayFromField(newDecoder(`[{"name":1}]`), "name")
+		if errField := errAs[errpath.ErrField](t, err); errField.Field != "name" {
+			t.Fatalf("got field %q, want name", errField.Field)
+		}
+
+		// the error of a value points into the whole input
+		err = om.UnmarshalJSONArrayFromField(newDecoder(`[{"name":"a"}, {"name":"b","age":"x"}]`), "name")
+		if errIdx := errAs[errpath.ErrIndex](t, err); errIdx.Index != 1 {
+			t.Fatalf("got index %d, want 1", errIdx.Index)
+		}
+
+		if semErr := errAs[json.SemanticError](t, err); semErr.JSONPointer != "/1/age" || semErr.ByteOffset != 33 {
+			t.Fatalf("got pointer %q at offset %d, want /1/age at 33", semErr.JSONPointer, semErr.ByteOffset)
+		}
+	})
+}
@@ -0,0 +1,257 @@
+package ordmap
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"strconv"
+
+	"github.com/MarkRosemaker/errpath"
+)
+
+var (
+	_ json.MarshalerTo     = (*Node)(nil)
+	_ json.UnmarshalerFrom = (*Node)(nil)
+)
+
+// Object is a JSON object in an ordered JSON document.
+type Object = OrderedMap[string, Node]
+
+// Array is a JSON array in an ordered JSON document.
+type Array []Node
+
+// Node is a value in an ordered JSON document.
+// Objects keep the order of their members and scalars keep their encoding,
+// so decoding and encoding a document again reproduces it.
+// The zero value is JSON null.
+type Node struct {
+	kind   jsontext.Kind
+	object Object
+	array  Array
+	raw    jsontext.Value // the encoding of a scalar
+}
+
+// NewObject returns a node for a JSON object.
+func NewObject(obj Object) Node {
+	if obj == nil {
+		obj = Object{}
+	}
+
+	return Node{kind: '{', object: obj}
+}
+
+// NewArray returns a node for a JSON array.
+func NewArray(nodes ...Node) Node {
+	if nodes == nil {
+		nodes = Array{}
+	}
+
+	return Node{kind: '[', array: nodes}
+}
+
+// NewString returns a node for a JSON string.
+// Invalid UTF-8 is replaced with the Unicode replacement character.
+func NewString(s string) Node {
+	raw, _ := jsontext.AppendQuote(nil, s)
+	return Node{kind: '"', raw: raw}
+}
+
+// NewInt returns a node for a JSON number.
+func NewInt(i int64) Node {
+	return Node{kind: '0', raw: strconv.AppendInt(nil, i, 10)}
+}
+
+// NewFloat returns a node for a JSON number.
+// NaN and infinities cannot be represented in JSON and result in null.
+func NewFloat(f float64) Node {
+	raw, err := json.Marshal(f)
+	if err != nil {
+		return Node{}
+	}
+
+	return Node{kind: '0', raw: raw}
+}
+
+// NewBool returns a node for a JSON boolean.
+func NewBool(b bool) Node {
+	if b {
+		return Node{kind: 't', raw: jsontext.Value("true")}
+	}
+
+	return Node{kind: 'f', raw: jsontext.Value("false")}
+}
+
+// Kind returns the kind of the JSON value, which is 'n' for null, 'f' for false, 't' for true,
+// '"' for a string, '0' for a number, '{' for an object and '[' for an array.
+func (n Node) Kind() jsontext.Kind {
+	if n.kind == 0 {
+		return 'n'
+	}
+
+	return n.kind
+}
+
+// IsNull reports whether the node is JSON null.
+func (n Node) IsNull() bool { return n.Kind() == 'n' }
+
+// AsObject returns the object and whether the node is a JSON object.
+func (n Node) AsObject() (Object, bool) { return n.object, n.kind == '{' }
+
+// AsArray returns the array and whether the node is a JSON array.
+func (n Node) AsArray() (Array, bool) { return n.array, n.kind == '[' }
+
+// AsString returns the string and whether the node is a JSON string.
+func (n Node) AsString() (string, bool) {
+	if n.kind != '"' {
+		return "", false
+	}
+
+	s, err := jsontext.AppendUnquote(nil, n.raw)
+	return string(s), err == nil
+}
+
+// AsFloat returns the number and whether the node is a JSON number that fits into a float64.
+func (n Node) AsFloat() (float64, bool) {
+	if n.kind != '0' {
+		return 0, false
+	}
+
+	f, err := strconv.ParseFloat(string(n.raw), 64)
+	return f, err == nil
+}
+
+// AsInt returns the number and whether the node is a JSON number that is an integer fitting into an int64.
+func (n Node) AsInt() (int64, bool) {
+	if n.kind != '0' {
+		return 0, false
+	}
+
+	i, err := strconv.ParseInt(string(n.raw), 10, 64)
+	return i, err == nil
+}
+
+// AsBool returns the boolean and whether the node is a JSON boolean.
+func (n Node) AsBool() (bool, bool) {
+	return n.kind == 't', n.kind == 't' || n.kind == 'f'
+}
+
+// Get returns the member of a JSON object and whether the node is an object with that member.
+func (n Node) Get(key string) (Node, bool) {
+	obj, ok := n.AsObject()
+	if !ok {
+		return Node{}, false
+	}
+
+	return obj.Get(key)
+}
+
+// Index returns the element of a JSON array and whether the node is an array with that element.
+func (n Node) Index(i int) (Node, bool) {
+	arr, ok := n.AsArray()
+	if !ok || i < 0 || i >= len(arr) {
+		return Node{}, false
+	}
+
+	return arr[i], true
+}
+
+// Clone returns a deep copy of the node, so changing the objects and arrays of one does not change the other.
+func (n Node) Clone() Node {
+	switch n.kind {
+	case '{':
+		obj := make(Object, len(n.object))
+		for k, v := range n.object {
+			obj[k] = Value[Node]{V: v.V.Clone(), idx: v.idx}
+		}
+
+		return Node{kind: n.kind, object: obj}
+	case '[':
+		arr := make(Array, len(n.array))
+		for i, elem := range n.array {
+			arr[i] = elem.Clone()
+		}
+
+		return Node{kind: n.kind, array: arr}
+	default:
+		return n // the encoding of a scalar is never changed
+	}
+}
+
+// String returns the node encoded as JSON.
+func (n Node) String() string {
+	b, err := json.Marshal(n)
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(b)
+}
+
+// UnmarshalJSONFrom decodes any JSON value, keeping the order of object members and the encoding of scalars.
+func (n *Node) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	switch kind := dec.PeekKind(); kind {
+	case '{':
+		var obj Object
+		if err := obj.UnmarshalJSONFrom(dec); err != nil {
+			return err
+		}
+
+		*n = Node{kind: kind, object: obj}
+	case '[':
+		if _, err := dec.ReadToken(); err != nil {
+			return err // should never fail
+		}
+
+		arr := Array{}
+		for i := 0; dec.PeekKind() != ']'; i++ {
+			var elem Node
+			if err := elem.UnmarshalJSONFrom(dec); err != nil {
+				return &errpath.ErrIndex{Index: i, Err: err}
+			}
+
+			arr = append(arr, elem)
+		}
+
+		if _, err := dec.ReadToken(); err != nil {
+			return err // consume ']', should not fail
+		}
+
+		*n = Node{kind: kind, array: arr}
+	default:
+		raw, err := dec.ReadValue()
+		if err != nil {
+			return err
+		}
+
+		if kind == 'n' {
+			kind = 0
+		}
+
+		*n = Node{kind: kind, raw: raw.Clone()}
+	}
+
+	return nil
+}
+
+// MarshalJSONTo encodes the node, keeping the order of object members and the encoding of scalars.
+func (n Node) MarshalJSONTo(enc *jsontext.Encoder) error {
+	switch n.kind {
+	case 0:
+		return enc.WriteToken(jsontext.Null)
+	case '{':
+		return n.object.MarshalJSONTo(enc)
+	case '[':
+		if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+			return err
+		}
+
+		for i, elem := range n.array {
+			if err := elem.MarshalJSONTo(enc); err != nil {
+				return &errpath.ErrIndex{Index: i, Err: err}
+			}
+		}
+
+		return enc.WriteToken(jsontext.EndArray)
+	default:
+		return enc.WriteValue(n.raw)
+	}
+}
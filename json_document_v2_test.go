@@ -0,0 +1,186 @@
+package ordmap_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func TestNode(t *testing.T) {
+	t.Parallel()
+
+	const doc = `{"openapi":"3.1.0","paths":{"/users":{"get":{"responses":{"200":{"description":"OK"}}}},"/a":{}},` +
+		`"x-numbers":[1.0,-2e10,3,1234567890123456789012],"x-flags":[true,false,null],"x-text":"é\n\"","x-empty":[]}`
+
+	var n ordmap.Node
+	if err := json.Unmarshal([]byte(doc), &n); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 10 {
+		got, err := json.Marshal(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(got) != doc {
+			t.Fatalf("got: %v, want: %v", string(got), doc)
+		}
+	}
+
+	if n.Kind() != '{' {
+		t.Fatalf("got: %v, want: {", n.Kind())
+	}
+
+	obj, ok := n.AsObject()
+	if !ok {
+		t.Fatal("expected an object")
+	}
+
+	if got, want := collectKeys(obj), []string{"openapi", "paths", "x-numbers", "x-flags", "x-text", "x-empty"}; !slices.Equal(got, want) {
+		t.Fatalf("got: %v, want: %v", got, want)
+	}
+
+	paths, _ := n.Get("paths")
+	users, _ := paths.Get("/users")
+	get, _ := users.Get("get")
+	responses, _ := get.Get("responses")
+	ok200, _ := responses.Get("200")
+	description, _ := ok200.Get("description")
+
+	if s, ok := description.AsString(); !ok || s != "OK" {
+		t.Fatalf("got: %q, %t", s, ok)
+	}
+
+	if _, ok := description.Get("foo"); ok {
+		t.Fatal("a string has no members")
+	}
+
+	if _, ok := paths.Get("/missing"); ok {
+		t.Fatal("expected a missing member")
+	}
+
+	numbers, _ := n.Get("x-numbers")
+	if arr, ok := numbers.AsArray(); !ok || len(arr) != 4 {
+		t.Fatalf("got: %v, %t", arr, ok)
+	}
+
+	if f, ok := numbers.Index(1); !ok {
+		t.Fatal("expected an element")
+	} else if v, ok := f.AsFloat(); !ok || v != -2e10 {
+		t.Fatalf("got: %v, %t", v, ok)
+	} else if _, ok := f.AsInt(); ok {
+		t.Fatal("-2e10 is not written as an integer")
+	}
+
+	if i, _ := numbers.Index(2); i.Kind() != '0' {
+		t.Fatalf("got: %v", i.Kind())
+	} else if v, ok := i.AsInt(); !ok || v != 3 {
+		t.Fatalf("got: %v, %t", v, ok)
+	}
+
+	if _, ok := numbers.Index(4); ok {
+		t.Fatal("expected no element")
+	}
+
+	if _, ok := n.Index(0); ok {
+		t.Fatal("an object has no elements")
+	}
+
+	flags, _ := n.Get("x-flags")
+	for i, want := range []struct {
+		kind jsontext.Kind
+		b    bool
+		ok   bool
+	}{{'t', true, true}, {'f', false, true}, {'n', false, false}} {
+		f, _ := flags.Index(i)
+		if f.Kind() != want.kind {
+			t.Fatalf("got: %v, want: %v", f.Kind(), want.kind)
+		}
+
+		if b, ok := f.AsBool(); b != want.b || ok != want.ok {
+			t.Fatalf("got: %t, %t", b, ok)
+		}
+	}
+
+	if null, _ := flags.Index(2); !null.IsNull() {
+		t.Fatal("expected null")
+	}
+
+	text, _ := n.Get("x-text")
+	if s, ok := text.AsString(); !ok || s != "é\n\"" {
+		t.Fatalf("got: %q, %t", s, ok)
+	}
+
+	// wrong kinds
+	if _, ok := text.AsObject(); ok {
+		t.Fatal("expected no object")
+	}
+
+	if _, ok := text.AsArray(); ok {
+		t.Fatal("expected no array")
+	}
+
+	if _, ok := text.AsFloat(); ok {
+		t.Fatal("expected no number")
+	}
+
+	if _, ok := text.AsInt(); ok {
+		t.Fatal("expected no number")
+	}
+
+	if _, ok := n.AsString(); ok {
+		t.Fatal("expected no string")
+	}
+}
+
+func TestNode_New(t *testing.T) {
+	t.Parallel()
+
+	obj := ordmap.Object{}
+	obj.Set("string", ordmap.NewString("foo"))
+	obj.Set("int", ordmap.NewInt(-42))
+	obj.Set("float", ordmap.NewFloat(1.5))
+	obj.Set("nan", ordmap.NewFloat(math.NaN()))
+	obj.Set("true", ordmap.NewBool(true))
+	obj.Set("false", ordmap.NewBool(false))
+	obj.Set("null", ordmap.Node{})
+	obj.Set("array", ordmap.NewArray(ordmap.NewInt(1), ordmap.NewArray()))
+	obj.Set("object", ordmap.NewObject(nil))
+
+	const want = `{"string":"foo","int":-42,"float":1.5,"nan":null,"true":true,"false":false,"null":null,"array":[1,[]],"object":{}}`
+	if got := ordmap.NewObject(obj).String(); got != want {
+		t.Fatalf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestNode_Errors(t *testing.T) {
+	t.Parallel()
+
+	var n ordmap.Node
+
+	err := json.Unmarshal([]byte(`{"a":[1,{"b":}]}`), &n)
+
+	errKey := errAs[errpath.ErrKey](t, err)
+	if errKey.Key != "a" {
+		t.Fatalf("got: %v, want: a", errKey.Key)
+	}
+
+	errIdx := errAs[errpath.ErrIndex](t, errKey.Err)
+	if errIdx.Index != 1 {
+		t.Fatalf("got: %v, want: 1", errIdx.Index)
+	}
+
+	if err := json.Unmarshal([]byte(`[1,`), &n); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if err := json.Unmarshal([]byte(``), &n); err == nil {
+		t.Fatal("expected error")
+	}
+}
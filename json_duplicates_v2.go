@@ -0,0 +1,116 @@
+package ordmap
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+)
+
+// DuplicateKeys decides what happens when a JSON object contains the same key more than once.
+// Choose it with WithDuplicateKeys.
+// The decoder only passes on duplicate keys if jsontext.AllowDuplicateNames(true) is set,
+// otherwise it rejects them itself.
+type DuplicateKeys int
+
+const (
+	// DuplicateKeysLastAtLast keeps the last value at the position of the last occurrence.
+	// This is the default.
+	DuplicateKeysLastAtLast DuplicateKeys = iota
+	// DuplicateKeysLastAtFirst keeps the last value at the position of the first occurrence.
+	DuplicateKeysLastAtFirst
+	// DuplicateKeysFirst keeps the first value at the position of the first occurrence.
+	DuplicateKeysFirst
+	// DuplicateKeysReject fails with an ErrDuplicateKey at the second occurrence.
+	DuplicateKeysReject
+)
+
+// WithDuplicateKeys returns an option that makes UnmarshalJSONFrom, both the method of OrderedMap
+// and the helper function, handle duplicate keys as chosen:
+//
+//	err := json.Unmarshal(data, &om, jsontext.AllowDuplicateNames(true),
+//		ordmap.WithDuplicateKeys(ordmap.DuplicateKeysReject))
+//
+// It is a json.WithUnmarshalers option, so it replaces other such options, e.g. OrderedObjects.
+// To use it with other unmarshalers, join them with json.JoinUnmarshalers and DuplicateKeys.Unmarshalers.
+func WithDuplicateKeys(dup DuplicateKeys) json.Options {
+	return json.WithUnmarshalers(dup.Unmarshalers())
+}
+
+// Unmarshalers returns the unmarshalers that WithDuplicateKeys registers.
+func (dup DuplicateKeys) Unmarshalers() *json.Unmarshalers {
+	return json.UnmarshalFromFunc(func(dec *jsontext.Decoder, opt *duplicateKeysOption) error {
+		*opt = duplicateKeysOption(dup)
+		return dec.SkipValue()
+	})
+}
+
+// duplicateKeysOption is decoded by the unmarshalers of WithDuplicateKeys to pass the policy on.
+type duplicateKeysOption DuplicateKeys
+
+// duplicateKeys returns the policy set with WithDuplicateKeys, or DuplicateKeysLastAtLast.
+func duplicateKeys(opts json.Options) DuplicateKeys {
+	uns, ok := json.GetOption(opts, json.WithUnmarshalers)
+	if !ok || uns == nil {
+		return DuplicateKeysLastAtLast
+	}
+
+	// without the unmarshaler of WithDuplicateKeys, the zero value is decoded
+	var opt duplicateKeysOption
+	if err := json.Unmarshal([]byte("0"), &opt, json.WithUnmarshalers(uns)); err != nil {
+		return DuplicateKeysLastAtLast
+	}
+
+	return DuplicateKeys(opt)
+}
+
+// UnmarshalJSONFromCollectSlices unmarshals an ordered map and collects all values of a duplicate key
+// at the position of its first occurrence.
+func UnmarshalJSONFromCollectSlices[K comparable, V any](om *OrderedMap[K, []V], dec *jsontext.Decoder) error {
+	return UnmarshalJSONFromCollect(om, dec, getIndex[[]V], setIndex[[]V],
+		func(vals Value[[]V], v V) Value[[]V] {
+			vals.V = append(vals.V, v)
+			return vals
+		})
+}
+
+// UnmarshalJSONFromCollect is a helper function to unmarshal an ordered map setting the indices in order
+// and collecting all values of a duplicate key at the position of its first occurrence.
+// appendValue adds a value to the collected values of a key, which are the zero value for a new key.
+func UnmarshalJSONFromCollect[M ~map[K]R, K comparable, R any, V any](
+	m *M, dec *jsontext.Decoder,
+	getIndex func(R) int,
+	setIndex func(R, int) R,
+	appendValue func(R, V) R,
+) error {
+	if null, err := beginObject(dec); err != nil || null {
+		if null {
+			*m = nil
+		}
+
+		return err
+	}
+
+	// create the map
+	*m = M{}
+
+	i := 1 // start at 1 to avoid confusion with zero values
+
+	return decodeMembers(dec, func(key K) error {
+		var v V
+		if err := json.UnmarshalDecode(dec, &v, dec.Options()); err != nil {
+			return err
+		}
+
+		if vals, ok := (*m)[key]; ok {
+			idx := getIndex(vals)
+			(*m)[key] = setIndex(appendValue(vals, v), idx)
+
+			return nil
+		}
+
+		var vals R
+		(*m)[key] = setIndex(appendValue(vals, v), i)
+		i++
+
+		return nil
+	})
+}
@@ -0,0 +1,203 @@
+package ordmap_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func TestWithDuplicateKeys(t *testing.T) {
+	t.Parallel()
+
+	const data = `{"a":{"bar":1},"b":{"bar":2},"a":{"bar":3},"c":{"bar":4}}`
+
+	for _, tc := range []struct {
+		name string
+		dup  ordmap.DuplicateKeys
+		want string
+	}{
+		{"last at last", ordmap.DuplicateKeysLastAtLast, `{"b":{"foo":"","bar":2},"a":{"foo":"","bar":3},"c":{"foo":"","bar":4}}`},
+		{"last at first", ordmap.DuplicateKeysLastAtFirst, `{"a":{"foo":"","bar":3},"b":{"foo":"","bar":2},"c":{"foo":"","bar":4}}`},
+		{"first", ordmap.DuplicateKeysFirst, `{"a":{"foo":"","bar":1},"b":{"foo":"","bar":2},"c":{"foo":"","bar":4}}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Run("user defined ordered map", func(t *testing.T) {
+				var om UserDefinedOrderedMap
+				if err := json.Unmarshal([]byte(data), &om, jsontext.AllowDuplicateNames(true),
+					ordmap.WithDuplicateKeys(tc.dup)); err != nil {
+					t.Fatal(err)
+				}
+
+				testDuplicates(t, &om, tc.want)
+			})
+
+			t.Run("ordered map", func(t *testing.T) {
+				var om OrderedMap
+				if err := json.Unmarshal([]byte(data), &om, jsontext.AllowDuplicateNames(true),
+					ordmap.WithDuplicateKeys(tc.dup)); err != nil {
+					t.Fatal(err)
+				}
+
+				testDuplicates(t, &om, tc.want)
+			})
+		})
+	}
+
+	t.Run("default", func(t *testing.T) {
+		var om OrderedMap
+		if err := json.Unmarshal([]byte(data), &om, jsontext.AllowDuplicateNames(true)); err != nil {
+			t.Fatal(err)
+		}
+
+		testDuplicates(t, &om, `{"b":{"foo":"","bar":2},"a":{"foo":"","bar":3},"c":{"foo":"","bar":4}}`)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		var om OrderedMap
+		err := json.Unmarshal([]byte(data), &om, jsontext.AllowDuplicateNames(true),
+			ordmap.WithDuplicateKeys(ordmap.DuplicateKeysReject))
+
+		errKey := errAs[errpath.ErrKey](t, err)
+		if errKey.Key != "a" {
+			t.Fatalf("got: %v, want: a", errKey.Key)
+		} else if !errors.Is(err, ordmap.ErrDuplicateKey) {
+			t.Fatalf("got: %v, want: %v", err, ordmap.ErrDuplicateKey)
+		}
+	})
+
+	t.Run("nested with ordered objects", func(t *testing.T) {
+		var om ordmap.OrderedMap[string, any]
+		if err := json.Unmarshal([]byte(`{"x":{"a":1,"b":2,"a":3}}`), &om, jsontext.AllowDuplicateNames(true),
+			json.WithUnmarshalers(json.JoinUnmarshalers(
+				ordmap.DuplicateKeysFirst.Unmarshalers(),
+				json.UnmarshalFromFunc(func(dec *jsontext.Decoder, v *any) error {
+					if dec.PeekKind() != '{' {
+						return errors.ErrUnsupported
+					}
+
+					var om ordmap.OrderedMap[string, any]
+					if err := om.UnmarshalJSONFrom(dec); err != nil {
+						return err
+					}
+
+					*v = om
+
+					return nil
+				})))); err != nil {
+			t.Fatal(err)
+		}
+
+		testDuplicates(t, &om, `{"x":{"a":1,"b":2}}`)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var om OrderedMap
+		if err := json.Unmarshal([]byte(`[]`), &om,
+			ordmap.WithDuplicateKeys(ordmap.DuplicateKeysFirst)); err == nil {
+			t.Fatal("expected error")
+		}
+
+		err := json.Unmarshal([]byte(`{"a":{},"a":1}`), &om, jsontext.AllowDuplicateNames(true),
+			ordmap.WithDuplicateKeys(ordmap.DuplicateKeysLastAtFirst))
+
+		errKey := errAs[errpath.ErrKey](t, err)
+		if errKey.Key != "a" {
+			t.Fatalf("got: %v, want: a", errKey.Key)
+		}
+	})
+}
+
+func testDuplicates(t *testing.T, om json.MarshalerTo, want string) {
+	t.Helper()
+
+	got, err := json.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != want {
+		t.Fatalf("got: %v, want: %v", string(got), want)
+	}
+}
+
+func TestUnmarshalJSONFromCollect(t *testing.T) {
+	t.Parallel()
+
+	collect := json.WithUnmarshalers(json.UnmarshalFromFunc(
+		func(dec *jsontext.Decoder, om *ordmap.OrderedMap[string, []int]) error {
+			return ordmap.UnmarshalJSONFromCollectSlices(om, dec)
+		}))
+
+	var om ordmap.OrderedMap[string, []int]
+	if err := json.Unmarshal([]byte(`{"a":1,"b":2,"a":3,"c":4,"a":5}`), &om,
+		jsontext.AllowDuplicateNames(true),
+		collect); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"a":[1,3,5],"b":[2],"c":[4]}`; string(got) != want {
+		t.Fatalf("got: %v, want: %v", string(got), want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"a":"1"}`), &om,
+		collect); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if err := json.Unmarshal([]byte(`1`), &om,
+		collect); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// collected holds all values of a key of a user defined ordered map.
+type collected struct {
+	Values []int
+	idx    int
+}
+
+func TestUnmarshalJSONFromCollect_UserDefined(t *testing.T) {
+	t.Parallel()
+
+	getIdx := func(c *collected) int { return c.idx }
+	setIdx := func(c *collected, i int) *collected { c.idx = i; return c }
+
+	var om map[string]*collected
+	if err := json.Unmarshal([]byte(`{"a":1,"b":2,"a":3,"c":4,"a":5}`), &om,
+		jsontext.AllowDuplicateNames(true),
+		json.WithUnmarshalers(json.UnmarshalFromFunc(
+			func(dec *jsontext.Decoder, om *map[string]*collected) error {
+				return ordmap.UnmarshalJSONFromCollect(om, dec, getIdx, setIdx,
+					func(c *collected, v int) *collected {
+						if c == nil {
+							c = &collected{}
+						}
+
+						c.Values = append(c.Values, v)
+
+						return c
+					})
+			}))); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for k, c := range ordmap.ByIndex(om, getIdx) {
+		got = append(got, fmt.Sprint(k, c.Values))
+	}
+
+	if want := []string{"a[1 3 5]", "b[2]", "c[4]"}; !slices.Equal(got, want) {
+		t.Fatalf("got: %v, want: %v", got, want)
+	}
+}
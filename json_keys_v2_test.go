@@ -0,0 +1,125 @@
+package ordmap_test
+
+import (
+	"encoding/json/v2"
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/MarkRosemaker/ordmap"
+)
+
+// point is a key type that is formatted by its text marshalling methods.
+type point struct{ X, Y int }
+
+func (p point) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, "%d,%d", p.X, p.Y), nil
+}
+
+func (p *point) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d,%d", &p.X, &p.Y)
+	return err
+}
+
+// name is a string key type with a name.
+type name string
+
+// flag is a boolean key type with a name.
+type flag bool
+
+func testKeys[K comparable](t *testing.T, keys []K, want string) {
+	t.Helper()
+
+	om := ordmap.OrderedMap[K, string]{}
+	for i, k := range keys {
+		om.Set(k, fmt.Sprint(i))
+	}
+
+	got, err := json.Marshal(&om)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != want {
+		t.Fatalf("got: %s, want: %s", got, want)
+	}
+
+	var back ordmap.OrderedMap[K, string]
+	if err := json.Unmarshal(got, &back); err != nil {
+		t.Fatal(err)
+	}
+
+	i := 0
+	for k, v := range back.ByIndex() {
+		if k != keys[i] || v != fmt.Sprint(i) {
+			t.Fatalf("entry %d: got %v: %v, want %v: %d", i, k, v, keys[i], i)
+		}
+
+		i++
+	}
+
+	if i != len(keys) {
+		t.Fatalf("got %d entries, want %d", i, len(keys))
+	}
+}
+
+func TestJSONKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("int", func(t *testing.T) {
+		testKeys(t, []int{3, -1, 0}, `{"3":"0","-1":"1","0":"2"}`)
+	})
+
+	t.Run("int8", func(t *testing.T) {
+		testKeys(t, []int8{-128, 127}, `{"-128":"0","127":"1"}`)
+	})
+
+	t.Run("uint", func(t *testing.T) {
+		testKeys(t, []uint64{18446744073709551615, 7}, `{"18446744073709551615":"0","7":"1"}`)
+	})
+
+	t.Run("float", func(t *testing.T) {
+		testKeys(t, []float64{1.5, -2, 1e21}, `{"1.5":"0","-2":"1","1e+21":"2"}`)
+	})
+
+	t.Run("bool", func(t *testing.T) {
+		testKeys(t, []bool{true, false}, `{"true":"0","false":"1"}`)
+	})
+
+	t.Run("named bool", func(t *testing.T) {
+		testKeys(t, []flag{false, true}, `{"false":"0","true":"1"}`)
+	})
+
+	t.Run("named string", func(t *testing.T) {
+		testKeys(t, []name{"b", "a"}, `{"b":"0","a":"1"}`)
+	})
+
+	t.Run("text marshaler", func(t *testing.T) {
+		testKeys(t, []point{{1, 2}, {-3, 4}}, `{"1,2":"0","-3,4":"1"}`)
+	})
+
+	t.Run("standard text marshaler", func(t *testing.T) {
+		testKeys(t, []netip.Addr{
+			netip.MustParseAddr("10.0.0.1"),
+			netip.MustParseAddr("::1"),
+		}, `{"10.0.0.1":"0","::1":"1"}`)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, tc := range []struct {
+			name string
+			data string
+			dst  any
+		}{
+			{"int", `{"x":"0"}`, &ordmap.OrderedMap[int, string]{}},
+			{"int overflow", `{"300":"0"}`, &ordmap.OrderedMap[uint8, string]{}},
+			{"float", `{"1.5.1":"0"}`, &ordmap.OrderedMap[float64, string]{}},
+			{"bool", `{"yes":"0"}`, &ordmap.OrderedMap[bool, string]{}},
+			{"text unmarshaler", `{"1":"0"}`, &ordmap.OrderedMap[point, string]{}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				errAs[json.SemanticError](t, json.Unmarshal([]byte(tc.data), tc.dst))
+			})
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package ordmap
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+)
+
+var (
+	_ json.MarshalerTo     = (*Lazy[any])(nil)
+	_ json.UnmarshalerFrom = (*Lazy[any])(nil)
+)
+
+// LazyMap is an ordered map that decodes its values only when they are accessed.
+// Unmarshalling it records the raw JSON of each value and its index,
+// and marshalling it writes the values that were never accessed as they were,
+// apart from whitespace and string escapes, which follow the options of the encoder
+// (see jsontext.PreserveRawStrings). This saves work for large documents of which only a few entries are needed.
+// JSON null results in a nil *Lazy, which behaves like a value decoded from null.
+type LazyMap[K comparable, V any] = OrderedMap[K, *Lazy[V]]
+
+// Lazy is a JSON value that is decoded when it is first accessed.
+// The zero value holds the zero value of V. It is not safe for concurrent use.
+type Lazy[V any] struct {
+	raw  jsontext.Value
+	opts json.Options
+	v    V
+}
+
+// SetLazy sets the value of the key in the lazy map, creating the lazy value if the key is missing or null.
+// An existing key keeps its position.
+func SetLazy[K comparable, V any](m *LazyMap[K, V], key K, v V) {
+	if l, _ := m.Get(key); l != nil {
+		l.Set(v)
+		return
+	}
+
+	m.Set(key, NewLazy(v))
+}
+
+// NewLazy returns a lazy value that is already decoded.
+func NewLazy[V any](v V) *Lazy[V] {
+	return &Lazy[V]{v: v}
+}
+
+// Get returns the value, decoding it from the raw JSON on first access with the options it was unmarshalled with.
+// If decoding fails, it reports the error and tries again on the next access.
+// Once decoded, the value is marshalled instead of the raw JSON, so changes to a value a pointer refers to are kept.
+func (l *Lazy[V]) Get() (V, error) {
+	if l == nil {
+		var zero V
+		return zero, nil
+	}
+
+	if l.raw != nil {
+		var v V
+		if err := json.Unmarshal(l.raw, &v, l.opts); err != nil {
+			return v, err
+		}
+
+		*l = Lazy[V]{v: v}
+	}
+
+	return l.v, nil
+}
+
+// Set replaces the value. Unlike the other methods, it needs a non-nil receiver,
+// so use SetLazy to set an entry of a LazyMap that may be missing or null.
+func (l *Lazy[V]) Set(v V) {
+	*l = Lazy[V]{v: v}
+}
+
+// Raw returns the raw JSON of a value that was not decoded yet, and nil otherwise.
+func (l *Lazy[V]) Raw() jsontext.Value {
+	if l == nil {
+		return nil
+	}
+
+	return l.raw
+}
+
+// IsDecoded reports whether the value was decoded or set, i.e. whether it is no longer marshalled from the raw JSON.
+func (l *Lazy[V]) IsDecoded() bool {
+	return l == nil || l.raw == nil
+}
+
+// UnmarshalJSONFrom records the raw JSON value without decoding it.
+func (l *Lazy[V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	raw, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+
+	*l = Lazy[V]{raw: raw.Clone(), opts: dec.Options()}
+
+	return nil
+}
+
+// MarshalJSONTo writes the raw JSON value as it was unmarshalled if the value was not decoded,
+// and encodes the value otherwise.
+func (l *Lazy[V]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if l.raw != nil {
+		return enc.WriteValue(l.raw)
+	}
+
+	return json.MarshalEncode(enc, l.v, enc.Options())
+}
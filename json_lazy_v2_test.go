@@ -0,0 +1,147 @@
+package ordmap_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func TestLazyMap(t *testing.T) {
+	t.Parallel()
+
+	const data = `{"b":{"foo": "\u0078",  "bar": 1.0E0},"a":{"foo":"y","bar":2},"c":null,"d":{ "foo" : "z" }}`
+
+	var lm ordmap.LazyMap[string, ValueWithIndex]
+	if err := json.Unmarshal([]byte(data), &lm); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := collectKeys(lm), []string{"b", "a", "c", "d"}; !slices.Equal(got, want) {
+		t.Fatalf("got: %v, want: %v", got, want)
+	}
+
+	b, _ := lm.Get("b")
+	if b.IsDecoded() || string(b.Raw()) != `{"foo": "\u0078",  "bar": 1.0E0}` {
+		t.Fatalf("got raw: %s", b.Raw())
+	}
+
+	// access one value and change another
+	a, _ := lm.Get("a")
+
+	v, err := a.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Foo != "y" || v.Bar != 2 || !a.IsDecoded() || a.Raw() != nil {
+		t.Fatalf("got: %+v", v)
+	}
+
+	d, _ := lm.Get("d")
+	d.Set(ValueWithIndex{Foo: "w", Bar: 3})
+
+	c, _ := lm.Get("c")
+	if v, err := c.Get(); err != nil || v != (ValueWithIndex{}) {
+		t.Fatalf("got: %+v, %v", v, err)
+	}
+
+	// the values are written as they were except for the whitespace, which the encoder formats
+	got, err := json.Marshal(&lm, jsontext.PreserveRawStrings(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"b":{"foo":"\u0078","bar":1.0E0},"a":{"foo":"y","bar":2},"c":null,"d":{"foo":"w","bar":3}}`; string(got) != want {
+		t.Fatalf("got: %s, want: %s", got, want)
+	}
+
+	t.Run("decoding error", func(t *testing.T) {
+		var lm ordmap.LazyMap[string, int]
+		if err := json.Unmarshal([]byte(`{"a":1,"b":"x"}`), &lm); err != nil {
+			t.Fatal(err) // not decoded yet
+		}
+
+		b, _ := lm.Get("b")
+		if _, err := b.Get(); err == nil {
+			t.Fatal("expected error")
+		}
+
+		if b.IsDecoded() {
+			t.Fatal("value should still be raw")
+		}
+
+		lm.Set("c", ordmap.NewLazy(3))
+
+		got, err := json.Marshal(&lm)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `{"a":1,"b":"x","c":3}`; string(got) != want {
+			t.Fatalf("got: %s, want: %s", got, want)
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		var lm ordmap.LazyMap[string, any]
+		if err := json.Unmarshal([]byte(`{"a":{"z":1,"y":2}}`), &lm, ordmap.OrderedObjects()); err != nil {
+			t.Fatal(err)
+		}
+
+		a, _ := lm.Get("a")
+
+		v, err := a.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := v.(ordmap.OrderedMap[string, any]); !ok {
+			t.Fatalf("got: %T, want ordered map", v)
+		}
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var l ordmap.Lazy[int]
+		if v, err := l.Get(); err != nil || v != 0 || !l.IsDecoded() {
+			t.Fatalf("got: %v, %v", v, err)
+		}
+
+		got, err := json.Marshal(&l)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(got) != `0` {
+			t.Fatalf("got: %s, want: 0", got)
+		}
+	})
+
+	t.Run("set lazy", func(t *testing.T) {
+		var lm ordmap.LazyMap[string, int]
+		if err := json.Unmarshal([]byte(`{"a":null,"b":1}`), &lm); err != nil {
+			t.Fatal(err)
+		}
+
+		kept, _ := lm.Get("b")
+
+		ordmap.SetLazy(&lm, "a", 2) // null
+		ordmap.SetLazy(&lm, "b", 3) // not decoded yet
+		ordmap.SetLazy(&lm, "c", 4) // missing
+
+		got, err := json.Marshal(&lm)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `{"a":2,"b":3,"c":4}`; string(got) != want {
+			t.Fatalf("got: %s, want: %s", got, want)
+		}
+
+		if v, _ := kept.Get(); v != 3 {
+			t.Fatalf("got: %d, want: 3", v)
+		}
+	})
+}
@@ -1,9 +1,12 @@
 package ordmap
 
 import (
+	"encoding"
 	"encoding/json/jsontext"
 	"encoding/json/v2"
 	"fmt"
+	"reflect"
+	"strconv"
 
 	"github.com/MarkRosemaker/errpath"
 )
@@ -16,15 +19,22 @@ func (om *OrderedMap[_, _]) MarshalJSONTo(enc *jsontext.Encoder) error {
 }
 
 // MarshalJSONTo marshals an ordered map by encoding its key-value pairs in order.
+// Like json v2 does for Go maps, it encodes a nil map as JSON null if json.FormatNilMapAsNull is set.
 func MarshalJSONTo[M ByIndexer[K, V], K comparable, V any](
 	m M, enc *jsontext.Encoder,
 ) error {
+	if isNilMap(m) {
+		if asNull, _ := json.GetOption(enc.Options(), json.FormatNilMapAsNull); asNull {
+			return enc.WriteToken(jsontext.Null)
+		}
+	}
+
 	if err := enc.WriteToken(jsontext.BeginObject); err != nil {
 		return err // should never fail
 	}
 
 	for k, v := range m.ByIndex() {
-		if err := json.MarshalEncode(enc, k, enc.Options()); err != nil {
+		if err := marshalKey(enc, k); err != nil {
 			return err
 		}
 
@@ -35,3 +45,33 @@ func MarshalJSONTo[M ByIndexer[K, V], K comparable, V any](
 
 	return enc.WriteToken(jsontext.EndObject)
 }
+
+// marshalKey encodes a key as a JSON object name following the rules of json v2 for Go map keys,
+// which formats strings, numbers and text marshalers as JSON strings.
+// In addition, booleans are formatted as "true" and "false".
+func marshalKey[K comparable](enc *jsontext.Encoder, k K) error {
+	if rv := reflect.ValueOf(k); rv.Kind() == reflect.Bool && !isTextKey[K]() {
+		return enc.WriteToken(jsontext.String(strconv.FormatBool(rv.Bool())))
+	}
+
+	return json.MarshalEncode(enc, k, enc.Options())
+}
+
+// isTextKey reports whether the key type is formatted by its text marshalling methods.
+func isTextKey[K comparable]() bool {
+	var k K
+	_, isMarshaler := any(k).(encoding.TextMarshaler)
+	_, isUnmarshaler := any(&k).(encoding.TextUnmarshaler)
+
+	return isMarshaler || isUnmarshaler
+}
+
+// isNilMap reports whether the value is a nil map or a pointer to one.
+func isNilMap(v any) bool {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	return rv.Kind() == reflect.Map && rv.IsNil()
+}
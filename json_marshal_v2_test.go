@@ -201,3 +201,43 @@ func errAs[T any, E interface {
 
 	return target
 }
+
+func TestMarshalJSONTo_Nil(t *testing.T) {
+	t.Parallel()
+
+	type container struct {
+		User    UserDefinedOrderedMap `json:"user"`
+		Ordered OrderedMap            `json:"ordered"`
+		Pointer *OrderedMapPointer    `json:"pointer"`
+	}
+
+	for _, tc := range []struct {
+		name string
+		opts []json.Options
+		want string
+	}{
+		{"default", nil, `{"user":{},"ordered":{},"pointer":null}`},
+		{"nil map as null", []json.Options{json.FormatNilMapAsNull(true)}, `{"user":null,"ordered":null,"pointer":null}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.Marshal(container{}, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != tc.want {
+				t.Fatalf("got: %v, want: %v", string(got), tc.want)
+			}
+
+			// an empty map is not nil
+			got, err = json.Marshal(container{User: UserDefinedOrderedMap{}, Ordered: OrderedMap{}}, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want := `{"user":{},"ordered":{},"pointer":null}`; string(got) != want {
+				t.Fatalf("got: %v, want: %v", string(got), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package ordmap
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+)
+
+// MergePatch applies a JSON Merge Patch (RFC 7396) to the target and returns the result.
+// Patched members keep their position, new members are added at the end in the order of the patch
+// and members set to null in the patch are removed, so untouched members keep their order.
+// Neither the target nor the patch is changed.
+func MergePatch(target, patch Node) Node {
+	return mergePatch(target.Clone(), patch)
+}
+
+// mergePatch applies the patch to the target, which it changes, and clones the values it takes from the patch.
+func mergePatch(target, patch Node) Node {
+	patchObj, ok := patch.AsObject()
+	if !ok {
+		return patch.Clone()
+	}
+
+	targetObj, ok := target.AsObject()
+	if !ok {
+		targetObj = Object{}
+	}
+
+	for k, v := range patchObj.ByIndex() {
+		if v.IsNull() {
+			targetObj.Delete(k)
+			continue
+		}
+
+		old, _ := targetObj.Get(k)
+		targetObj.Set(k, mergePatch(old, v))
+	}
+
+	return NewObject(targetObj)
+}
+
+// MergePatchJSON applies a JSON Merge Patch to a JSON document. See MergePatch.
+func MergePatchJSON(target, patch []byte, opts ...json.Options) ([]byte, error) {
+	var t, p Node
+	if err := json.Unmarshal(target, &t, opts...); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(patch, &p, opts...); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(MergePatch(t, p), opts...)
+}
+
+// MergePatchMap applies a JSON Merge Patch to an ordered map such as OrderedMap. See MergePatch.
+// The map is converted through JSON, so it needs JSON methods that keep its order,
+// and the patched values must fit its value type.
+func MergePatchMap[M ~map[K]R, K comparable, R any](m *M, patch Node, opts ...json.Options) error {
+	var target Node
+	if err := convertJSON(m, &target, opts); err != nil {
+		return err
+	}
+
+	// decode into a new map, so that removed keys are gone and m is unchanged on failure
+	var result M
+	if err := convertJSON(MergePatch(target, patch), &result, opts); err != nil {
+		return err
+	}
+
+	*m = result
+
+	return nil
+}
+
+// CreateMergePatchMap returns a JSON Merge Patch that turns the original ordered map into the modified one.
+// See CreateMergePatch. The maps are converted through JSON like in MergePatchMap.
+func CreateMergePatchMap[M ~map[K]R, K comparable, R any](original, modified M, opts ...json.Options) (Node, error) {
+	var o, m Node
+	if err := convertJSON(&original, &o, opts); err != nil {
+		return Node{}, err
+	}
+
+	if err := convertJSON(&modified, &m, opts); err != nil {
+		return Node{}, err
+	}
+
+	return CreateMergePatch(o, m), nil
+}
+
+// convertJSON encodes in as JSON and decodes it into out.
+func convertJSON(in, out any, opts []json.Options) error {
+	b, err := json.Marshal(in, opts...)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, out, opts...)
+}
+
+// CreateMergePatch returns a JSON Merge Patch that turns the original into the modified document.
+// It lists changed and new members in the order of the modified document, followed by removed members.
+// Since merge patches cannot express it, applying the patch adds new members at the end
+// instead of their position in the modified document, and a member set to null in an object is not kept.
+func CreateMergePatch(original, modified Node) Node {
+	origObj, isObj := original.AsObject()
+	modObj, ok := modified.AsObject()
+	if !isObj || !ok {
+		return modified.Clone()
+	}
+
+	patch := Object{}
+
+	for k, mv := range modObj.ByIndex() {
+		ov, exists := origObj.Get(k)
+		switch {
+		case !exists:
+			patch.Set(k, mv.Clone())
+		case ov.Equal(mv):
+			// unchanged
+		case ov.Kind() == '{' && mv.Kind() == '{':
+			patch.Set(k, CreateMergePatch(ov, mv))
+		default:
+			patch.Set(k, mv.Clone())
+		}
+	}
+
+	for k := range origObj.ByIndex() {
+		if !modObj.Has(k) {
+			patch.Set(k, Node{})
+		}
+	}
+
+	return NewObject(patch)
+}
+
+// Equal reports whether two nodes represent the same JSON value.
+// Objects are equal if they have the same members regardless of their order,
+// strings are compared after unescaping and numbers by their value.
+func (n Node) Equal(other Node) bool {
+	if n.Kind() != other.Kind() {
+		return false
+	}
+
+	switch n.Kind() {
+	case '{':
+		if n.object.Len() != other.object.Len() {
+			return false
+		}
+
+		for k, v := range n.object {
+			ov, ok := other.object[k]
+			if !ok || !v.V.Equal(ov.V) {
+				return false
+			}
+		}
+
+		return true
+	case '[':
+		if len(n.array) != len(other.array) {
+			return false
+		}
+
+		for i := range n.array {
+			if !n.array[i].Equal(other.array[i]) {
+				return false
+			}
+		}
+
+		return true
+	case 'n', 't', 'f':
+		return true
+	default:
+		return string(canonical(n.raw)) == string(canonical(other.raw))
+	}
+}
+
+// canonical returns the canonical encoding of a scalar JSON value (RFC 8785).
+func canonical(raw jsontext.Value) jsontext.Value {
+	raw = raw.Clone()
+	if err := raw.Canonicalize(); err != nil {
+		return nil
+	}
+
+	return raw
+}
@@ -0,0 +1,262 @@
+package ordmap_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func mustNode(t *testing.T, data string) ordmap.Node {
+	t.Helper()
+
+	var n ordmap.Node
+	if err := json.Unmarshal([]byte(data), &n); err != nil {
+		t.Fatal(err)
+	}
+
+	return n
+}
+
+func TestMergePatch(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		target, patch, want string
+	}{
+		// the examples of RFC 7396
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		// order
+		{`{"z":1,"y":{"b":1,"a":2},"x":3}`, `{"w":0,"y":{"c":3,"a":null,"b":4},"z":null}`, `{"y":{"b":4,"c":3},"x":3,"w":0}`},
+	} {
+		got := ordmap.MergePatch(mustNode(t, tc.target), mustNode(t, tc.patch))
+		if got.String() != tc.want {
+			t.Fatalf("%s + %s: got: %s, want: %s", tc.target, tc.patch, got, tc.want)
+		}
+	}
+
+	t.Run("inputs unchanged", func(t *testing.T) {
+		target := mustNode(t, `{"a":{"b":1},"c":2}`)
+		patch := mustNode(t, `{"a":{"b":null},"c":null}`)
+
+		if got := ordmap.MergePatch(target, patch).String(); got != `{"a":{}}` {
+			t.Fatalf("got: %s", got)
+		}
+
+		if got := target.String(); got != `{"a":{"b":1},"c":2}` {
+			t.Fatalf("target changed: %s", got)
+		}
+	})
+
+	t.Run("edit the result, the inputs stay the same", func(t *testing.T) {
+		target := mustNode(t, `{"server":{"port":80},"name":"x"}`)
+		patch := mustNode(t, `{"name":"y","tags":{"a":1}}`)
+
+		res := ordmap.MergePatch(target, patch)
+		if err := ordmap.SetPointer(&res, "/server/port", 1); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.SetPointer(&res, "/tags/a", 2); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := res.String(), `{"server":{"port":1},"name":"y","tags":{"a":2}}`; got != want {
+			t.Fatalf("got: %s, want: %s", got, want)
+		}
+
+		if got := target.String(); got != `{"server":{"port":80},"name":"x"}` {
+			t.Fatalf("target changed: %s", got)
+		}
+
+		if got := patch.String(); got != `{"name":"y","tags":{"a":1}}` {
+			t.Fatalf("patch changed: %s", got)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		got, err := ordmap.MergePatchJSON([]byte(`{"b":1,"a":2}`), []byte(`{"a":3,"c":4}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `{"b":1,"a":3,"c":4}`; string(got) != want {
+			t.Fatalf("got: %s, want: %s", got, want)
+		}
+
+		if _, err := ordmap.MergePatchJSON([]byte(`{`), []byte(`{}`)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		original, modified, want string
+	}{
+		{`{"a":1,"b":{"c":1,"d":2},"e":[1]}`, `{"a":1,"b":{"c":1,"d":3},"e":[1],"f":true}`, `{"b":{"d":3},"f":true}`},
+		{`{"a":1,"b":2}`, `{"b":2}`, `{"a":null}`},
+		{`{"a":{"b":1}}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"a":1.0,"b":"A"}`, `{"b":"A","a":1}`, `{}`},
+		{`[1]`, `[2]`, `[2]`},
+	} {
+		original, modified := mustNode(t, tc.original), mustNode(t, tc.modified)
+
+		patch := ordmap.CreateMergePatch(original, modified)
+		if patch.String() != tc.want {
+			t.Fatalf("%s -> %s: got: %s, want: %s", tc.original, tc.modified, patch, tc.want)
+		}
+
+		if got := ordmap.MergePatch(original, patch); !got.Equal(modified) {
+			t.Fatalf("%s -> %s: patched: %s", tc.original, tc.modified, got)
+		}
+	}
+
+	t.Run("edit the patch, the inputs stay the same", func(t *testing.T) {
+		original := mustNode(t, `{"a":{"b":1}}`)
+		modified := mustNode(t, `{"a":{"b":2},"c":{"d":1},"e":[1]}`)
+
+		patch := ordmap.CreateMergePatch(original, modified)
+		for _, ptr := range []string{"/a/b", "/c/d", "/e/0"} {
+			if err := ordmap.SetPointer(&patch, jsontext.Pointer(ptr), 9); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if got := modified.String(); got != `{"a":{"b":2},"c":{"d":1},"e":[1]}` {
+			t.Fatalf("modified changed: %s", got)
+		}
+
+		array := mustNode(t, `[{"a":1}]`)
+
+		patch = ordmap.CreateMergePatch(original, array)
+		if err := ordmap.SetPointer(&patch, "/0/a", 9); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := array.String(); got != `[{"a":1}]` {
+			t.Fatalf("modified changed: %s", got)
+		}
+	})
+}
+
+func TestNode_Equal(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		a, b string
+		want bool
+	}{
+		{`{"a":1,"b":[true,null]}`, `{"b":[true,null],"a":1}`, true},
+		{`{"a":1}`, `{"a":1,"b":2}`, false},
+		{`[1,2]`, `[2,1]`, false},
+		{`1e2`, `100`, true},
+		{`"é"`, `"\u00e9"`, true},
+		{`"1"`, `1`, false},
+		{`null`, `false`, false},
+	} {
+		if got := mustNode(t, tc.a).Equal(mustNode(t, tc.b)); got != tc.want {
+			t.Fatalf("%s == %s: got: %v, want: %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+
+	if !(ordmap.Node{}).Equal(mustNode(t, `null`)) {
+		t.Fatal("zero node should equal null")
+	}
+}
+
+func TestMergePatchMap(t *testing.T) {
+	t.Parallel()
+
+	var config ordmap.OrderedMap[string, ordmap.OrderedMap[string, int]]
+	if err := json.Unmarshal([]byte(`{"server":{"port":80,"timeout":5},"client":{"retries":3}}`), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ordmap.MergePatchMap(&config,
+		mustNode(t, `{"server":{"timeout":null,"workers":4},"cache":{"size":1},"client":null}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"server":{"port":80,"workers":4},"cache":{"size":1}}`; string(got) != want {
+		t.Fatalf("got: %s, want: %s", got, want)
+	}
+
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		var om UserDefinedOrderedMap
+		om.Set("b", &ValueWithIndex{Foo: "b"})
+		om.Set("a", &ValueWithIndex{Foo: "a"})
+
+		if err := ordmap.MergePatchMap(&om, mustNode(t, `{"b":{"bar":2},"c":{"foo":"c"}}`)); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := json.Marshal(&om)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `{"b":{"foo":"b","bar":2},"a":{"foo":"a","bar":0},"c":{"foo":"c","bar":0}}`; string(got) != want {
+			t.Fatalf("got: %s, want: %s", got, want)
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		before := config.Len()
+		if err := ordmap.MergePatchMap(&config, mustNode(t, `{"x":{"y":"z"}}`)); err == nil {
+			t.Fatal("expected error")
+		}
+
+		if config.Len() != before || config.Has("x") {
+			t.Fatal("map changed")
+		}
+	})
+
+	t.Run("create", func(t *testing.T) {
+		original, modified := newMap("a", 1, "b", 2, "c", 3), newMap("a", 1, "c", 4, "d", 5)
+
+		patch, err := ordmap.CreateMergePatchMap(original, modified)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `{"c":4,"d":5,"b":null}`; patch.String() != want {
+			t.Fatalf("got: %s, want: %s", patch, want)
+		}
+
+		if err := ordmap.MergePatchMap(&original, patch); err != nil {
+			t.Fatal(err)
+		}
+
+		if !slices.Equal(collectKeys(original), []string{"a", "c", "d"}) {
+			t.Fatalf("got: %v", collectKeys(original))
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package ordmap
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"reflect"
+)
+
+var (
+	_ DeepMergeUnmarshaler = (*OrderedMap[string, any])(nil)
+	_ DeepMergeUnmarshaler = (*Value[any])(nil)
+	_ DeepMergeUnmarshaler = (*Node)(nil)
+)
+
+// DeepMergeUnmarshaler is implemented by types that can merge a JSON object into their existing content,
+// recursing into values that implement it themselves.
+type DeepMergeUnmarshaler interface {
+	UnmarshalJSONFromDeepMerge(dec *jsontext.Decoder) error
+}
+
+// UnmarshalJSONFromMerge unmarshals the key-value pairs into the existing map.
+// Existing keys keep their position and get the new value, new keys are added at the end of the order.
+// To use it when unmarshalling, register it with json.WithUnmarshalers.
+func (om *OrderedMap[K, V]) UnmarshalJSONFromMerge(dec *jsontext.Decoder) error {
+	return UnmarshalJSONFromMerge(om, dec, getIndex, setIndex)
+}
+
+// UnmarshalJSONFromDeepMerge is like UnmarshalJSONFromMerge but merges objects into existing values
+// instead of replacing them, recursively for values that are ordered maps themselves.
+func (om *OrderedMap[K, V]) UnmarshalJSONFromDeepMerge(dec *jsontext.Decoder) error {
+	return UnmarshalJSONFromDeepMerge(om, dec, getIndex, setIndex)
+}
+
+// UnmarshalJSONFromDeepMerge merges a JSON object into the existing value.
+func (cs *Value[_]) UnmarshalJSONFromDeepMerge(dec *jsontext.Decoder) error {
+	return unmarshalDeepMerge(dec, &cs.V)
+}
+
+// UnmarshalJSONFromDeepMerge merges a JSON object into an existing object node.
+// Any other JSON value replaces the node.
+func (n *Node) UnmarshalJSONFromDeepMerge(dec *jsontext.Decoder) error {
+	if n.kind != '{' || dec.PeekKind() != '{' {
+		return n.UnmarshalJSONFrom(dec)
+	}
+
+	return n.object.UnmarshalJSONFromDeepMerge(dec)
+}
+
+// UnmarshalJSONFromMerge is a helper function to unmarshal into an existing ordered map.
+// Existing keys keep their index and get the new value, new keys are added after the highest index.
+func UnmarshalJSONFromMerge[M ~map[K]R, K comparable, R any](
+	m *M, dec *jsontext.Decoder,
+	getIndex func(R) int,
+	setIndex func(R, int) R,
+) error {
+	return unmarshalMerge(m, dec, getIndex, setIndex, false)
+}
+
+// UnmarshalJSONFromDeepMerge is like UnmarshalJSONFromMerge but merges objects into existing values
+// instead of replacing them, recursively for values that implement DeepMergeUnmarshaler.
+func UnmarshalJSONFromDeepMerge[M ~map[K]R, K comparable, R any](
+	m *M, dec *jsontext.Decoder,
+	getIndex func(R) int,
+	setIndex func(R, int) R,
+) error {
+	return unmarshalMerge(m, dec, getIndex, setIndex, true)
+}
+
+func unmarshalMerge[M ~map[K]R, K comparable, R any](
+	m *M, dec *jsontext.Decoder,
+	getIndex func(R) int,
+	setIndex func(R, int) R,
+	deep bool,
+) error {
+	if null, err := beginObject(dec); err != nil || null {
+		if null {
+			*m = nil
+		}
+
+		return err
+	}
+
+	// check if the map is nil and create it if it is
+	if *m == nil {
+		*m = M{}
+	}
+
+	next := highestIndex(*m, getIndex) + 1
+
+	return decodeMembers(dec, func(key K) error {
+		old, exists := (*m)[key]
+
+		var v R
+		if exists && deep {
+			v = old
+			if err := unmarshalDeepMerge(dec, &v); err != nil {
+				return err
+			}
+		} else if err := json.UnmarshalDecode(dec, &v, dec.Options()); err != nil {
+			return err
+		}
+
+		// keep the position of an existing key
+		if exists {
+			if idx := getIndex(old); idx != 0 {
+				(*m)[key] = setIndex(v, idx)
+				return nil
+			}
+		}
+
+		(*m)[key] = setIndex(v, next)
+		next++
+
+		return nil
+	})
+}
+
+// unmarshalDeepMerge decodes a JSON value into the existing value that ptr points to.
+// An object is merged into a DeepMergeUnmarshaler, also if it is held by an interface,
+// e.g. an ordered map decoded with OrderedObjects, replaces anything else held by an interface
+// and is merged into any other value as json.UnmarshalDecode does.
+// Any other JSON value replaces the existing value.
+func unmarshalDeepMerge(dec *jsontext.Decoder, ptr any) error {
+	rv := reflect.ValueOf(ptr).Elem()
+
+	if dec.PeekKind() != '{' {
+		rv.SetZero()
+		return json.UnmarshalDecode(dec, ptr, dec.Options())
+	}
+
+	if u, ok := ptr.(DeepMergeUnmarshaler); ok {
+		return u.UnmarshalJSONFromDeepMerge(dec)
+	}
+
+	if rv.Kind() == reflect.Interface && !rv.IsNil() {
+		elem := reflect.New(rv.Elem().Type())
+		elem.Elem().Set(rv.Elem())
+
+		if u, ok := elem.Interface().(DeepMergeUnmarshaler); ok {
+			if err := u.UnmarshalJSONFromDeepMerge(dec); err != nil {
+				return err
+			}
+
+			rv.Set(elem.Elem())
+
+			return nil
+		}
+
+		// the object replaces whatever else the interface holds
+		rv.SetZero()
+	}
+
+	return json.UnmarshalDecode(dec, ptr, dec.Options())
+}
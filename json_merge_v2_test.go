@@ -0,0 +1,177 @@
+package ordmap_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"strings"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func TestUnmarshalJSONFromMerge(t *testing.T) {
+	t.Parallel()
+
+	const (
+		defaults = `{"server":{"host":"localhost","port":80},"debug":false,"tags":["a"]}`
+		override = `{"log":"info","server":{"port":8080,"tls":true},"debug":true}`
+	)
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		var om UserDefinedOrderedMap
+		om.Set("foo", &ValueWithIndex{Foo: "a", Bar: 1})
+		om.Set("bar", &ValueWithIndex{Foo: "b", Bar: 2})
+		delete(om, "foo")
+		om["baz"] = &ValueWithIndex{Foo: "c", Bar: 3}
+
+		dec := jsontext.NewDecoder(strings.NewReader(`{"qux":{"bar":4},"bar":{"bar":5},"baz":{"foo":"d"}}`))
+		if err := ordmap.UnmarshalJSONFromMerge(&om, dec, getIndex, setIndex); err != nil {
+			t.Fatal(err)
+		}
+
+		// an uninitialized key is added like a new one
+		const want = `{"bar":{"foo":"","bar":5},"qux":{"foo":"","bar":4},"baz":{"foo":"d","bar":0}}`
+		if got, err := json.Marshal(&om); err != nil {
+			t.Fatal(err)
+		} else if string(got) != want {
+			t.Fatalf("got: %v, want: %v", string(got), want)
+		}
+
+		dec = jsontext.NewDecoder(strings.NewReader(`{"qux":{"foo":"e"},"new":{"bar":6}}`))
+		if err := ordmap.UnmarshalJSONFromDeepMerge(&om, dec, getIndex, setIndex); err != nil {
+			t.Fatal(err)
+		}
+
+		const wantDeep = `{"bar":{"foo":"","bar":5},"qux":{"foo":"e","bar":4},"baz":{"foo":"d","bar":0},"new":{"foo":"","bar":6}}`
+		if got, err := json.Marshal(&om); err != nil {
+			t.Fatal(err)
+		} else if string(got) != wantDeep {
+			t.Fatalf("got: %v, want: %v", string(got), wantDeep)
+		}
+	})
+
+	t.Run("ordered map", func(t *testing.T) {
+		var om ordmap.OrderedMap[string, any]
+		if err := json.Unmarshal([]byte(defaults), &om, ordmap.OrderedObjects()); err != nil {
+			t.Fatal(err)
+		}
+
+		// decode the nested server as an ordered map, too
+		if err := om.UnmarshalJSONFromMerge(jsontext.NewDecoder(strings.NewReader(override),
+			ordmap.OrderedObjects())); err != nil {
+			t.Fatal(err)
+		}
+
+		// the server is replaced as a whole
+		const want = `{"server":{"port":8080,"tls":true},"debug":true,"tags":["a"],"log":"info"}`
+		if got, err := json.Marshal(om); err != nil {
+			t.Fatal(err)
+		} else if string(got) != want {
+			t.Fatalf("got: %v, want: %v", string(got), want)
+		}
+	})
+
+	t.Run("ordered map deep", func(t *testing.T) {
+		var om ordmap.OrderedMap[string, any]
+		if err := json.Unmarshal([]byte(defaults), &om, ordmap.OrderedObjects()); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := json.Unmarshal([]byte(override), &om, ordmap.OrderedObjects(),
+			json.WithUnmarshalers(json.UnmarshalFromFunc(
+				func(dec *jsontext.Decoder, om *ordmap.OrderedMap[string, any]) error {
+					return om.UnmarshalJSONFromDeepMerge(dec)
+				}))); err != nil {
+			t.Fatal(err)
+		}
+
+		const want = `{"server":{"host":"localhost","port":8080,"tls":true},"debug":true,"tags":["a"],"log":"info"}`
+		if got, err := json.Marshal(om); err != nil {
+			t.Fatal(err)
+		} else if string(got) != want {
+			t.Fatalf("got: %v, want: %v", string(got), want)
+		}
+
+		// a value of another kind replaces the existing one
+		if err := om.UnmarshalJSONFromDeepMerge(jsontext.NewDecoder(
+			strings.NewReader(`{"server":"remote","tags":{"b":1}}`))); err != nil {
+			t.Fatal(err)
+		}
+
+		const wantReplaced = `{"server":"remote","debug":true,"tags":{"b":1},"log":"info"}`
+		if got, err := json.Marshal(om); err != nil {
+			t.Fatal(err)
+		} else if string(got) != wantReplaced {
+			t.Fatalf("got: %v, want: %v", string(got), wantReplaced)
+		}
+	})
+
+	t.Run("nested ordered maps", func(t *testing.T) {
+		var om ordmap.OrderedMap[string, ordmap.OrderedMap[string, int]]
+		if err := json.Unmarshal([]byte(`{"a":{"x":1,"y":2},"b":{"z":3}}`), &om); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := om.UnmarshalJSONFromDeepMerge(jsontext.NewDecoder(
+			strings.NewReader(`{"b":{"w":4},"a":{"x":5},"c":{"v":6}}`))); err != nil {
+			t.Fatal(err)
+		}
+
+		const want = `{"a":{"x":5,"y":2},"b":{"z":3,"w":4},"c":{"v":6}}`
+		if got, err := json.Marshal(om); err != nil {
+			t.Fatal(err)
+		} else if string(got) != want {
+			t.Fatalf("got: %v, want: %v", string(got), want)
+		}
+	})
+
+	t.Run("node", func(t *testing.T) {
+		var n ordmap.Node
+		if err := json.Unmarshal([]byte(defaults), &n); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := n.UnmarshalJSONFromDeepMerge(jsontext.NewDecoder(strings.NewReader(override))); err != nil {
+			t.Fatal(err)
+		}
+
+		const want = `{"server":{"host":"localhost","port":8080,"tls":true},"debug":true,"tags":["a"],"log":"info"}`
+		if got := n.String(); got != want {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+
+		if err := n.UnmarshalJSONFromDeepMerge(jsontext.NewDecoder(strings.NewReader(`[1]`))); err != nil {
+			t.Fatal(err)
+		} else if got := n.String(); got != `[1]` {
+			t.Fatalf("got: %v, want: [1]", got)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var om ordmap.OrderedMap[string, ordmap.OrderedMap[string, int]]
+		if err := om.UnmarshalJSONFromMerge(jsontext.NewDecoder(strings.NewReader(`[]`))); err == nil {
+			t.Fatal("expected error")
+		}
+
+		if err := om.UnmarshalJSONFromDeepMerge(jsontext.NewDecoder(strings.NewReader(`{"a":{"b":{}}}`))); err == nil {
+			t.Fatal("expected error")
+		}
+
+		om.Set("a", ordmap.OrderedMap[string, int]{})
+
+		err := om.UnmarshalJSONFromDeepMerge(jsontext.NewDecoder(strings.NewReader(`{"a":{"b":"c"}}`)))
+
+		errKey := errAs[errpath.ErrKey](t, err)
+		if errKey.Key != "a" {
+			t.Fatalf("got: %v, want: a", errKey.Key)
+		}
+
+		var v ordmap.OrderedMap[string, any]
+		v.Set("a", ordmap.OrderedMap[string, any]{})
+
+		if err := v.UnmarshalJSONFromDeepMerge(jsontext.NewDecoder(strings.NewReader(`{"a":{"b":}}`))); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package ordmap
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+)
+
+// OrderedObjects returns an option that decodes every JSON object into an `any` as an OrderedMap[string, any].
+// Without it, only the top level of an OrderedMap[string, any] is ordered, while nested objects decode into
+// a plain map[string]any and lose their order.
+// With it, nested objects at every depth keep their order, also inside arrays,
+// so marshalling the result reproduces the original order of the keys.
+//
+//	var om ordmap.OrderedMap[string, any]
+//	err := json.Unmarshal(data, &om, ordmap.OrderedObjects())
+func OrderedObjects() json.Options {
+	return json.WithUnmarshalers(json.UnmarshalFromFunc(unmarshalOrderedObject))
+}
+
+// unmarshalOrderedObject decodes a JSON object into an OrderedMap[string, any]
+// and leaves any other JSON value to the default behavior.
+func unmarshalOrderedObject(dec *jsontext.Decoder, v *any) error {
+	if dec.PeekKind() != '{' {
+		return errors.ErrUnsupported
+	}
+
+	var om OrderedMap[string, any]
+	if err := om.UnmarshalJSONFrom(dec); err != nil {
+		return err
+	}
+
+	*v = om
+
+	return nil
+}
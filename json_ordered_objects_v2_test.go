@@ -0,0 +1,80 @@
+package ordmap_test
+
+import (
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func TestOrderedObjects(t *testing.T) {
+	t.Parallel()
+
+	const want = `{"z":{"y":1,"x":[{"c":true,"b":null,"a":"a"},2,"3"]},"m":{},"a":{"q":{"p":{"o":0}}}}`
+
+	t.Run("ordered map", func(t *testing.T) {
+		var om ordmap.OrderedMap[string, any]
+		if err := json.Unmarshal([]byte(want), &om, ordmap.OrderedObjects()); err != nil {
+			t.Fatal(err)
+		}
+
+		nested, ok := om["z"].V.(ordmap.OrderedMap[string, any])
+		if !ok {
+			t.Fatalf("got: %T", om["z"].V)
+		}
+
+		arr, ok := nested["x"].V.([]any)
+		if !ok {
+			t.Fatalf("got: %T", nested["x"].V)
+		}
+
+		if _, ok := arr[0].(ordmap.OrderedMap[string, any]); !ok {
+			t.Fatalf("got: %T", arr[0])
+		}
+
+		for range 10 {
+			got, err := json.Marshal(om)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != want {
+				t.Fatalf("got: %v, want: %v", string(got), want)
+			}
+		}
+	})
+
+	t.Run("any", func(t *testing.T) {
+		var v any
+		if err := json.Unmarshal([]byte(want), &v, ordmap.OrderedObjects()); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(got) != want {
+			t.Fatalf("got: %v, want: %v", string(got), want)
+		}
+
+		v = nil
+		if err := json.Unmarshal([]byte(`"foo"`), &v, ordmap.OrderedObjects()); err != nil {
+			t.Fatal(err)
+		} else if v != "foo" {
+			t.Fatalf("got: %v, want: foo", v)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var om ordmap.OrderedMap[string, any]
+		err := json.Unmarshal([]byte(`{"a":{"b":{"c":}}}`), &om, ordmap.OrderedObjects())
+
+		errKey := errAs[errpath.ErrKey](t, err)
+		if errKey.Key != "a" {
+			t.Fatalf("got: %v, want: a", errKey.Key)
+		}
+	})
+}
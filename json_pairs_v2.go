@@ -0,0 +1,284 @@
+package ordmap
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+
+	"github.com/MarkRosemaker/errpath"
+)
+
+var (
+	_ json.MarshalerTo     = (*Pairs[string, any])(nil)
+	_ json.UnmarshalerFrom = (*Pairs[string, any])(nil)
+	_ json.MarshalerTo     = (*Entries[string, any])(nil)
+	_ json.UnmarshalerFrom = (*Entries[string, any])(nil)
+)
+
+// Pairs is an ordered map that is encoded as a JSON array of key-value pairs, e.g. [["a",1],["b",2]],
+// for consumers that cannot rely on the order of the members of a JSON object.
+// Convert an OrderedMap to Pairs and back to switch between the encodings.
+type Pairs[K comparable, V any] OrderedMap[K, V]
+
+// Entries is an ordered map that is encoded as a JSON array of objects with a key and a value,
+// e.g. [{"key":"a","value":1},{"key":"b","value":2}],
+// for consumers that cannot rely on the order of the members of a JSON object.
+// Convert an OrderedMap to Entries and back to switch between the encodings.
+type Entries[K comparable, V any] OrderedMap[K, V]
+
+// MarshalJSONTo marshals the key-value pairs in order as a JSON array of pairs.
+func (p *Pairs[K, V]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return (*OrderedMap[K, V])(p).MarshalJSONPairsTo(enc)
+}
+
+// UnmarshalJSONFrom unmarshals a JSON array of pairs in order and sets the indices.
+func (p *Pairs[K, V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return (*OrderedMap[K, V])(p).UnmarshalJSONPairsFrom(dec)
+}
+
+// MarshalJSONTo marshals the key-value pairs in order as a JSON array of entries.
+func (e *Entries[K, V]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return (*OrderedMap[K, V])(e).MarshalJSONEntriesTo(enc)
+}
+
+// UnmarshalJSONFrom unmarshals a JSON array of entries in order and sets the indices.
+func (e *Entries[K, V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return (*OrderedMap[K, V])(e).UnmarshalJSONEntriesFrom(dec)
+}
+
+// MarshalJSONPairsTo marshals the key-value pairs in order as a JSON array of pairs.
+// To use it when marshalling, register it with json.WithMarshalers or use Pairs.
+func (om *OrderedMap[K, V]) MarshalJSONPairsTo(enc *jsontext.Encoder) error {
+	return MarshalJSONPairsTo(om, enc)
+}
+
+// UnmarshalJSONPairsFrom unmarshals a JSON array of pairs in order and sets the indices.
+// To use it when unmarshalling, register it with json.WithUnmarshalers or use Pairs.
+func (om *OrderedMap[K, V]) UnmarshalJSONPairsFrom(dec *jsontext.Decoder) error {
+	return UnmarshalJSONPairsFrom(om, dec, setIndex)
+}
+
+// MarshalJSONEntriesTo marshals the key-value pairs in order as a JSON array of entries.
+// To use it when marshalling, register it with json.WithMarshalers or use Entries.
+func (om *OrderedMap[K, V]) MarshalJSONEntriesTo(enc *jsontext.Encoder) error {
+	return MarshalJSONEntriesTo(om, enc)
+}
+
+// UnmarshalJSONEntriesFrom unmarshals a JSON array of entries in order and sets the indices.
+// To use it when unmarshalling, register it with json.WithUnmarshalers or use Entries.
+func (om *OrderedMap[K, V]) UnmarshalJSONEntriesFrom(dec *jsontext.Decoder) error {
+	return UnmarshalJSONEntriesFrom(om, dec, setIndex)
+}
+
+// MarshalJSONPairsTo marshals an ordered map as a JSON array of pairs, each being an array of the key and the value.
+// Unlike in a JSON object, a key is encoded as any other JSON value, e.g. an integer key as a JSON number.
+// Like json v2 does for Go maps, it encodes a nil map as JSON null if json.FormatNilMapAsNull is set.
+func MarshalJSONPairsTo[M ByIndexer[K, V], K comparable, V any](
+	m M, enc *jsontext.Encoder,
+) error {
+	return marshalArray(m, enc, func(k K, v V) error {
+		if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+			return err
+		}
+
+		if err := json.MarshalEncode(enc, k, enc.Options()); err != nil {
+			return &errpath.ErrIndex{Index: 0, Err: err}
+		}
+
+		if err := json.MarshalEncode(enc, v, enc.Options()); err != nil {
+			return &errpath.ErrIndex{Index: 1, Err: err}
+		}
+
+		return enc.WriteToken(jsontext.EndArray)
+	})
+}
+
+// UnmarshalJSONPairsFrom is a helper function to unmarshal an ordered map from a JSON array of pairs
+// setting the indices in order. A key that appears more than once gets the value and position of its last pair.
+func UnmarshalJSONPairsFrom[M ~map[K]R, K comparable, R any](
+	m *M, dec *jsontext.Decoder,
+	setIndex func(R, int) R,
+) error {
+	return unmarshalArray(m, dec, setIndex, func() (key K, v R, err error) {
+		if kind := dec.PeekKind(); kind != '[' {
+			return key, v, &json.SemanticError{JSONKind: kind}
+		}
+
+		if _, err := dec.ReadToken(); err != nil {
+			return key, v, err // should never fail
+		}
+
+		if dec.PeekKind() == ']' {
+			return key, v, ErrInvalidPair
+		}
+
+		if err := json.UnmarshalDecode(dec, &key, dec.Options()); err != nil {
+			return key, v, &errpath.ErrIndex{Index: 0, Err: err}
+		}
+
+		if dec.PeekKind() == ']' {
+			return key, v, ErrInvalidPair
+		}
+
+		if err := json.UnmarshalDecode(dec, &v, dec.Options()); err != nil {
+			return key, v, &errpath.ErrIndex{Index: 1, Err: err}
+		}
+
+		if dec.PeekKind() != ']' {
+			return key, v, ErrInvalidPair
+		}
+
+		_, err = dec.ReadToken() // consume ']', should not fail
+
+		return key, v, err
+	})
+}
+
+// MarshalJSONEntriesTo marshals an ordered map as a JSON array of objects with the members "key" and "value".
+// Unlike in a JSON object, a key is encoded as any other JSON value, e.g. an integer key as a JSON number.
+// Like json v2 does for Go maps, it encodes a nil map as JSON null if json.FormatNilMapAsNull is set.
+func MarshalJSONEntriesTo[M ByIndexer[K, V], K comparable, V any](
+	m M, enc *jsontext.Encoder,
+) error {
+	return marshalArray(m, enc, func(k K, v V) error {
+		if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+			return err
+		}
+
+		if err := enc.WriteToken(jsontext.String("key")); err != nil {
+			return err
+		}
+
+		if err := json.MarshalEncode(enc, k, enc.Options()); err != nil {
+			return &errpath.ErrField{Field: "key", Err: err}
+		}
+
+		if err := enc.WriteToken(jsontext.String("value")); err != nil {
+			return err
+		}
+
+		if err := json.MarshalEncode(enc, v, enc.Options()); err != nil {
+			return &errpath.ErrField{Field: "value", Err: err}
+		}
+
+		return enc.WriteToken(jsontext.EndObject)
+	})
+}
+
+// UnmarshalJSONEntriesFrom is a helper function to unmarshal an ordered map from a JSON array of entries
+// setting the indices in order. Each entry must have a "key", a missing "value" leaves the zero value
+// and other members are ignored. A key that appears more than once gets the value and position of its last entry.
+func UnmarshalJSONEntriesFrom[M ~map[K]R, K comparable, R any](
+	m *M, dec *jsontext.Decoder,
+	setIndex func(R, int) R,
+) error {
+	return unmarshalArray(m, dec, setIndex, func() (key K, v R, err error) {
+		if null, err := beginObject(dec); err != nil || null {
+			if null {
+				err = &json.SemanticError{JSONKind: 'n'}
+			}
+
+			return key, v, err
+		}
+
+		hasKey := false
+		for dec.PeekKind() != '}' {
+			name, err := dec.ReadToken()
+			if err != nil {
+				return key, v, err
+			}
+
+			switch field := name.String(); field {
+			case "key":
+				if err := json.UnmarshalDecode(dec, &key, dec.Options()); err != nil {
+					return key, v, &errpath.ErrField{Field: field, Err: err}
+				}
+
+				hasKey = true
+			case "value":
+				if err := json.UnmarshalDecode(dec, &v, dec.Options()); err != nil {
+					return key, v, &errpath.ErrField{Field: field, Err: err}
+				}
+			default:
+				if err := dec.SkipValue(); err != nil {
+					return key, v, err
+				}
+			}
+		}
+
+		if _, err := dec.ReadToken(); err != nil {
+			return key, v, err // consume '}', should not fail
+		}
+
+		if !hasKey {
+			return key, v, &errpath.ErrField{Field: "key", Err: &errpath.ErrRequired{}}
+		}
+
+		return key, v, nil
+	})
+}
+
+// marshalArray encodes the key-value pairs in order as a JSON array, letting marshalPair encode each pair.
+func marshalArray[M ByIndexer[K, V], K comparable, V any](
+	m M, enc *jsontext.Encoder,
+	marshalPair func(K, V) error,
+) error {
+	if isNilMap(m) {
+		if asNull, _ := json.GetOption(enc.Options(), json.FormatNilMapAsNull); asNull {
+			return enc.WriteToken(jsontext.Null)
+		}
+	}
+
+	if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+		return err
+	}
+
+	i := 0
+	for k, v := range m.ByIndex() {
+		if err := marshalPair(k, v); err != nil {
+			return &errpath.ErrIndex{Index: i, Err: err}
+		}
+
+		i++
+	}
+
+	return enc.WriteToken(jsontext.EndArray)
+}
+
+// unmarshalArray decodes a JSON array into the map, letting unmarshalPair decode each key-value pair,
+// and sets the indices in order.
+func unmarshalArray[M ~map[K]R, K comparable, R any](
+	m *M, dec *jsontext.Decoder,
+	setIndex func(R, int) R,
+	unmarshalPair func() (K, R, error),
+) error {
+	switch kind := dec.PeekKind(); kind {
+	case 'n':
+		_, err := dec.ReadToken()
+		*m = nil
+
+		return err
+	case '[', 0: // zero means invalid or no input, which ReadToken reports
+		if _, err := dec.ReadToken(); err != nil {
+			return err
+		}
+	default:
+		return &json.SemanticError{JSONKind: kind}
+	}
+
+	// create the map
+	*m = M{}
+
+	for i := 0; dec.PeekKind() != ']'; i++ {
+		key, v, err := unmarshalPair()
+		if err != nil {
+			return &errpath.ErrIndex{Index: i, Err: err}
+		}
+
+		// set the variable in the map with the proper index, starting at 1
+		(*m)[key] = setIndex(v, i+1)
+	}
+
+	_, err := dec.ReadToken() // consume ']', should not fail
+
+	return err
+}
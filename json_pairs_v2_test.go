@@ -0,0 +1,228 @@
+package ordmap_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func TestPairs(t *testing.T) {
+	t.Parallel()
+
+	om := ordmap.OrderedMap[string, int]{}
+	om.Set("b", 1)
+	om.Set("a", 2)
+	om.Set("c", 3)
+
+	const want = `[["b",1],["a",2],["c",3]]`
+
+	p := ordmap.Pairs[string, int](om)
+
+	got, err := json.Marshal(&p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != want {
+		t.Fatalf("got: %s, want: %s", got, want)
+	}
+
+	var back ordmap.Pairs[string, int]
+	if err := json.Unmarshal(got, &back); err != nil {
+		t.Fatal(err)
+	}
+
+	// the converted map is encoded as an object in the same order
+	om = ordmap.OrderedMap[string, int](back)
+
+	got, err = json.Marshal(&om)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"b":1,"a":2,"c":3}`; string(got) != want {
+		t.Fatalf("got: %s, want: %s", got, want)
+	}
+
+	t.Run("integer keys", func(t *testing.T) {
+		var p ordmap.Pairs[int, string]
+		if err := json.Unmarshal([]byte(`[[3,"x"],[-1,"y"]]`), &p); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := json.Marshal(&p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `[[3,"x"],[-1,"y"]]`; string(got) != want {
+			t.Fatalf("got: %s, want: %s", got, want)
+		}
+	})
+
+	t.Run("duplicate key", func(t *testing.T) {
+		var p ordmap.Pairs[string, int]
+		if err := json.Unmarshal([]byte(`[["a",1],["b",2],["a",3]]`), &p); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := json.Marshal(&p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `[["b",2],["a",3]]`; string(got) != want {
+			t.Fatalf("got: %s, want: %s", got, want)
+		}
+	})
+
+	t.Run("null", func(t *testing.T) {
+		p := ordmap.Pairs[string, int]{}
+		if err := json.Unmarshal([]byte(`null`), &p); err != nil {
+			t.Fatal(err)
+		}
+
+		if p != nil {
+			t.Fatalf("got: %v, want nil", p)
+		}
+
+		got, err := json.Marshal(&p, json.FormatNilMapAsNull(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(got) != `null` {
+			t.Fatalf("got: %s, want: null", got)
+		}
+
+		got, err = json.Marshal(&p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(got) != `[]` {
+			t.Fatalf("got: %s, want: []", got)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, tc := range []struct {
+			data  string
+			index int
+			want  error
+		}{
+			{`[["a",1],["b"]]`, 1, ordmap.ErrInvalidPair},
+			{`[[]]`, 0, ordmap.ErrInvalidPair},
+			{`[["a",1,2]]`, 0, ordmap.ErrInvalidPair},
+		} {
+			var p ordmap.Pairs[string, int]
+
+			err := json.Unmarshal([]byte(tc.data), &p)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("%s: got: %v, want: %v", tc.data, err, tc.want)
+			}
+
+			if errIdx := errAs[errpath.ErrIndex](t, err); errIdx.Index != tc.index {
+				t.Fatalf("%s: got: %v, want index %d", tc.data, err, tc.index)
+			}
+		}
+
+		var p ordmap.Pairs[string, int]
+		errAs[json.SemanticError](t, json.Unmarshal([]byte(`{"a":1}`), &p))
+
+		err := json.Unmarshal([]byte(`[["a","x"]]`), &p)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		if want := `[0][1]`; !strings.Contains(err.Error(), want) {
+			t.Fatalf("got: %v, want path %s", err, want)
+		}
+	})
+}
+
+func TestEntries(t *testing.T) {
+	t.Parallel()
+
+	om := ordmap.OrderedMap[string, int]{}
+	om.Set("b", 1)
+	om.Set("a", 2)
+
+	const want = `[{"key":"b","value":1},{"key":"a","value":2}]`
+
+	got, err := json.Marshal(&om, json.WithMarshalers(
+		json.MarshalToFunc(func(enc *jsontext.Encoder, om *ordmap.OrderedMap[string, int]) error {
+			return om.MarshalJSONEntriesTo(enc)
+		})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != want {
+		t.Fatalf("got: %s, want: %s", got, want)
+	}
+
+	var back ordmap.Entries[string, int]
+	if err := json.Unmarshal([]byte(
+		`[{"value":1,"key":"b"},{"key":"a","value":2,"comment":"ignored"},{"key":"c"}]`,
+	), &back); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = json.Marshal(&back)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `[{"key":"b","value":1},{"key":"a","value":2},{"key":"c","value":0}]`; string(got) != want {
+		t.Fatalf("got: %s, want: %s", got, want)
+	}
+
+	t.Run("missing key", func(t *testing.T) {
+		var e ordmap.Entries[string, int]
+
+		err := json.Unmarshal([]byte(`[{"key":"a","value":1},{"value":2}]`), &e)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		errAs[errpath.ErrRequired](t, err)
+
+		if want := `[1].key`; !strings.Contains(err.Error(), want) {
+			t.Fatalf("got: %v, want path %s", err, want)
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		var e ordmap.Entries[string, int]
+
+		err := json.Unmarshal([]byte(`[{"key":"a","value":"x"}]`), &e)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		if want := `[0].value`; !strings.Contains(err.Error(), want) {
+			t.Fatalf("got: %v, want path %s", err, want)
+		}
+	})
+
+	t.Run("unmarshaler option", func(t *testing.T) {
+		var om ordmap.OrderedMap[string, int]
+		if err := json.Unmarshal([]byte(want), &om, json.WithUnmarshalers(
+			json.UnmarshalFromFunc(func(dec *jsontext.Decoder, om *ordmap.OrderedMap[string, int]) error {
+				return om.UnmarshalJSONEntriesFrom(dec)
+			})),
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		if v, _ := om.Get("a"); v != 2 {
+			t.Fatalf("got: %v, want: 2", v)
+		}
+	})
+}
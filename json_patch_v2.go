@@ -0,0 +1,320 @@
+package ordmap
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/MarkRosemaker/errpath"
+)
+
+// Patch is a JSON Patch (RFC 6902), a list of operations to apply to a JSON document in order.
+type Patch []Operation
+
+// Operation is an operation of a JSON Patch.
+//
+// As an extension, the "add", "move" and "copy" operations accept a position,
+// the zero-based position in the order of the ordered map the member is added to.
+// A "move" within the same ordered map keeps the position of the member it renames,
+// and a "move" to the same path with a position reorders the member.
+type Operation struct {
+	Op       string           `json:"op"`
+	From     jsontext.Pointer `json:"from,omitzero"`
+	Path     jsontext.Pointer `json:"path"`
+	Value    jsontext.Value   `json:"value,omitzero"`
+	Position *int             `json:"position,omitzero"`
+}
+
+var patchOps = []string{"add", "remove", "replace", "move", "copy", "test"}
+
+// ApplyPatch applies the patch to a copy of the document and returns it.
+// If an operation fails, it returns the original document and an error with the index of the operation.
+func ApplyPatch(doc Node, patch Patch) (Node, error) {
+	patched := doc.Clone()
+	if err := patch.Apply(&patched); err != nil {
+		return doc, err
+	}
+
+	return patched, nil
+}
+
+// Apply applies the operations in order to the document that v points to, which can be a Node,
+// an ordered map or any other value that the JSON pointer functions walk through, e.g. a struct.
+// New members are added at the end of ordered maps unless the operation has a position.
+// If an operation fails, it returns an error with the index of the operation and
+// the operations before it remain applied. Use ApplyPatch to apply all operations or none.
+func (p Patch) Apply(v any) error {
+	for i, op := range p {
+		if err := op.apply(v); err != nil {
+			return &errpath.ErrIndex{Index: i, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// apply applies the operation to the document that v points to.
+func (op Operation) apply(v any) error {
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return &errpath.ErrField{Field: "value", Err: &errpath.ErrRequired{}}
+		}
+	case "remove", "move", "copy":
+	default:
+		return &errpath.ErrField{Field: "op", Err: &errpath.ErrInvalid[string]{Value: op.Op, Enum: patchOps}}
+	}
+
+	switch op.Op {
+	case "add":
+		return op.add(v, op.Value)
+	case "remove":
+		return DeletePointer(v, op.Path)
+	case "replace":
+		if _, err := GetPointer(v, op.Path); err != nil {
+			return err
+		}
+
+		return SetPointer(v, op.Path, op.Value)
+	case "move":
+		return op.move(v)
+	case "copy":
+		val, err := GetPointer(v, op.From)
+		if err != nil {
+			return &errpath.ErrField{Field: "from", Err: err}
+		}
+
+		// encode the value so the copy does not share anything with the original
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+
+		return op.add(v, jsontext.Value(raw))
+	default: // test
+		return op.test(v)
+	}
+}
+
+// add adds the value at the path, at the position of the operation if it has one.
+func (op Operation) add(v any, val any) error {
+	if op.Position != nil {
+		return InsertPointer(v, op.Path, *op.Position, val)
+	}
+
+	return AddPointer(v, op.Path, val)
+}
+
+// move removes the value at from and adds it at the path.
+// Within the same ordered map, the member keeps its position unless the operation has one.
+func (op Operation) move(v any) error {
+	val, err := GetPointer(v, op.From)
+	if err != nil {
+		return &errpath.ErrField{Field: "from", Err: err}
+	}
+
+	if op.From == op.Path {
+		if op.Position == nil {
+			return nil
+		}
+
+		return InsertPointer(v, op.Path, *op.Position, val)
+	}
+
+	if strings.HasPrefix(string(op.Path), string(op.From)+"/") {
+		return fmt.Errorf("%w: cannot move %q into itself", ErrInvalidPointer, op.From)
+	}
+
+	// check that the value can be added before removing it, so that a failed move changes nothing
+	keys, ordered, err := keyOrder(v, op.Path.Parent())
+	if err != nil {
+		return err
+	}
+
+	if op.Position != nil && !ordered {
+		return fmt.Errorf("%w: cannot insert at a position of %q", errors.ErrUnsupported, op.Path.Parent())
+	}
+
+	if op.Position == nil && ordered && op.From.Parent() == op.Path.Parent() {
+		// rename the member in place, taking the position of the member it replaces out first
+		keys = slices.DeleteFunc(keys, func(k string) bool { return k == op.Path.LastToken() })
+		pos := slices.Index(keys, op.From.LastToken())
+
+		if err := DeletePointer(v, op.From); err != nil {
+			return err
+		}
+
+		return InsertPointer(v, op.Path, pos, val)
+	}
+
+	if err := DeletePointer(v, op.From); err != nil {
+		return err
+	}
+
+	return op.add(v, val)
+}
+
+// test compares the value at the path with the value of the operation.
+func (op Operation) test(v any) error {
+	val, err := GetPointer(v, op.Path)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	var got, want Node
+	if err := json.Unmarshal(raw, &got); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(op.Value, &want); err != nil {
+		return &errpath.ErrField{Field: "value", Err: err}
+	}
+
+	if !got.Equal(want) {
+		return fmt.Errorf("%w: got %s, want %s", ErrTestFailed, got, want)
+	}
+
+	return nil
+}
+
+// CreatePatch returns a JSON Patch that turns the original into the modified document.
+// For objects, it removes and adds members, changes their values and reorders them with "move" operations
+// that have a position, moving as few members as possible. Other values that differ are replaced.
+// The patch needs the position extension of Operation to reorder members.
+func CreatePatch(original, modified Node) (Patch, error) {
+	return createPatch("", original, modified)
+}
+
+func createPatch(ptr jsontext.Pointer, original, modified Node) (Patch, error) {
+	origObj, isObj := original.AsObject()
+	modObj, ok := modified.AsObject()
+	if isObj && ok {
+		return diffPatch(ptr, origObj, modObj, createPatch)
+	}
+
+	if original.Equal(modified) {
+		return nil, nil
+	}
+
+	return replaceOp(ptr, modified)
+}
+
+// DiffPatch returns a JSON Patch that turns the ordered map a into b,
+// where prefix is the JSON pointer to the map within the document.
+// It removes and adds keys, replaces values that are not equal and reorders the keys
+// with "move" operations that have a position, moving as few keys as possible.
+func DiffPatch[K comparable, V any](
+	prefix jsontext.Pointer, a, b ByIndexer[K, V],
+	equal func(V, V) bool,
+) (Patch, error) {
+	return diffPatch(prefix, a, b, func(ptr jsontext.Pointer, va, vb V) (Patch, error) {
+		if equal(va, vb) {
+			return nil, nil
+		}
+
+		return replaceOp(ptr, vb)
+	})
+}
+
+// diffPatch returns the operations that turn the keys of a into the keys of b in order,
+// followed by the operations that diffValue returns for the values of keys in both.
+func diffPatch[K comparable, V any](
+	prefix jsontext.Pointer, a, b ByIndexer[K, V],
+	diffValue func(jsontext.Pointer, V, V) (Patch, error),
+) (Patch, error) {
+	ptr := func(k K) jsontext.Pointer { return prefix.AppendToken(keyToken(reflect.ValueOf(k))) }
+
+	aVals := map[K]V{}
+	for k, v := range a.ByIndex() {
+		aVals[k] = v
+	}
+
+	var bKeys []K
+	bVals := map[K]V{}
+	for k, v := range b.ByIndex() {
+		bKeys = append(bKeys, k)
+		bVals[k] = v
+	}
+
+	var patch, valuePatch Patch
+
+	// remove the keys that are not in b
+	var current []K
+	for k := range a.ByIndex() {
+		if _, ok := bVals[k]; ok {
+			current = append(current, k)
+		} else {
+			patch = append(patch, Operation{Op: "remove", Path: ptr(k)})
+		}
+	}
+
+	// keep the longest common subsequence of the remaining keys in their places
+	common := slices.DeleteFunc(slices.Clone(bKeys), func(k K) bool {
+		_, ok := aVals[k]
+		return !ok
+	})
+
+	stay := map[K]bool{}
+	for _, pair := range lcs(current, common) {
+		stay[current[pair[0]]] = true
+	}
+
+	// add and move the other keys directly after the key that precedes them in b
+	for i, k := range bKeys {
+		va, exists := aVals[k]
+		if exists {
+			ops, err := diffValue(ptr(k), va, bVals[k])
+			if err != nil {
+				return nil, err
+			}
+
+			valuePatch = append(valuePatch, ops...)
+
+			if stay[k] {
+				continue
+			}
+
+			current = slices.DeleteFunc(current, func(c K) bool { return c == k })
+		}
+
+		pos := 0
+		if i > 0 {
+			pos = slices.Index(current, bKeys[i-1]) + 1
+		}
+
+		current = slices.Insert(current, pos, k)
+
+		if exists {
+			patch = append(patch, Operation{Op: "move", From: ptr(k), Path: ptr(k), Position: &pos})
+			continue
+		}
+
+		raw, err := json.Marshal(bVals[k])
+		if err != nil {
+			return nil, &errpath.ErrKey{Key: fmt.Sprint(k), Err: err}
+		}
+
+		patch = append(patch, Operation{Op: "add", Path: ptr(k), Value: raw, Position: &pos})
+	}
+
+	return append(patch, valuePatch...), nil
+}
+
+// replaceOp returns a "replace" operation with the encoded value.
+func replaceOp(ptr jsontext.Pointer, v any) (Patch, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return Patch{{Op: "replace", Path: ptr, Value: raw}}, nil
+}
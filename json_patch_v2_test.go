@@ -0,0 +1,295 @@
+package ordmap_test
+
+import (
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+// setOnlyMap is an ordered map that can be changed with Set, but has no InsertAt method.
+type setOnlyMap map[string]*ValueWithIndex
+
+func (om setOnlyMap) ByIndex() iter.Seq2[string, *ValueWithIndex] {
+	return ordmap.ByIndex(om, getIndex)
+}
+
+func (om *setOnlyMap) Set(key string, v *ValueWithIndex) {
+	ordmap.Set(om, key, v, getIndex, setIndex)
+}
+
+func mustPatch(t *testing.T, data string) ordmap.Patch {
+	t.Helper()
+
+	var p ordmap.Patch
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestApplyPatch(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		doc, patch, want string
+	}{
+		// examples of RFC 6902
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"foo":"bar","baz":"qux"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{
+			`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"","value":[1]}]`, `[1]`},
+		// order extensions
+		{`{"a":1,"b":2,"c":3}`, `[{"op":"add","path":"/x","value":0,"position":1}]`, `{"a":1,"x":0,"b":2,"c":3}`},
+		{`{"a":1,"b":2,"c":3}`, `[{"op":"move","from":"/b","path":"/y"}]`, `{"a":1,"y":2,"c":3}`},
+		{`{"a":1,"b":2,"c":3}`, `[{"op":"move","from":"/c","path":"/a"}]`, `{"b":2,"a":3}`},
+		{`{"a":1,"b":2,"c":3}`, `[{"op":"move","from":"/a","path":"/c"}]`, `{"c":1,"b":2}`},
+		{`{"a":1,"b":2,"c":3}`, `[{"op":"move","from":"/c","path":"/c","position":0}]`, `{"c":3,"a":1,"b":2}`},
+		{`{"a":1,"b":2,"c":3}`, `[{"op":"move","from":"/a","path":"/z","position":2}]`, `{"b":2,"c":3,"z":1}`},
+		{`{"a":{"x":1},"b":2}`, `[{"op":"copy","from":"/a","path":"/c","position":0},{"op":"add","path":"/c/y","value":2}]`, `{"c":{"x":1,"y":2},"a":{"x":1},"b":2}`},
+		{`{"a":{"y":1,"x":2}}`, `[{"op":"test","path":"/a","value":{"x":2,"y":1.0}}]`, `{"a":{"y":1,"x":2}}`},
+	} {
+		got, err := ordmap.ApplyPatch(mustNode(t, tc.doc), mustPatch(t, tc.patch))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.patch, err)
+		}
+
+		if got.String() != tc.want {
+			t.Fatalf("%s: got: %s, want: %s", tc.patch, got, tc.want)
+		}
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			doc, patch string
+			index      int
+			want       error
+		}{
+			{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, 0, ordmap.ErrTestFailed},
+			{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, 0, ordmap.ErrKeyNotFound},
+			{`{"a":[1]}`, `[{"op":"remove","path":"/a/0"},{"op":"remove","path":"/a/0"}]`, 1, ordmap.ErrOutOfRange},
+			{`{"a":1}`, `[{"op":"replace","path":"/b","value":2}]`, 0, ordmap.ErrKeyNotFound},
+			{`{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/c"}]`, 0, ordmap.ErrInvalidPointer},
+			{`{"a":1}`, `[{"op":"copy","from":"/b","path":"/c"}]`, 0, ordmap.ErrKeyNotFound},
+		} {
+			doc := mustNode(t, tc.doc)
+
+			got, err := ordmap.ApplyPatch(doc, mustPatch(t, tc.patch))
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("%s: got: %v, want: %v", tc.patch, err, tc.want)
+			}
+
+			if errIdx := errAs[errpath.ErrIndex](t, err); errIdx.Index != tc.index {
+				t.Fatalf("%s: got index %d, want %d", tc.patch, errIdx.Index, tc.index)
+			}
+
+			if got.String() != doc.String() {
+				t.Fatalf("%s: document changed: %s", tc.patch, got)
+			}
+		}
+
+		_, err := ordmap.ApplyPatch(mustNode(t, `{}`), mustPatch(t, `[{"op":"add","path":"/a"}]`))
+		errAs[errpath.ErrRequired](t, err)
+
+		_, err = ordmap.ApplyPatch(mustNode(t, `{}`), mustPatch(t, `[{"op":"fix","path":"/a"}]`))
+		if errInvalid := errAs[errpath.ErrInvalid[string]](t, err); errInvalid.Value != "fix" {
+			t.Fatalf("got: %v", err)
+		}
+	})
+
+	t.Run("ordered map", func(t *testing.T) {
+		om := ordmap.OrderedMap[string, int]{}
+		om.Set("a", 1)
+		om.Set("b", 2)
+
+		if err := mustPatch(t, `[
+			{"op":"add","path":"/c","value":3,"position":0},
+			{"op":"move","from":"/a","path":"/d"},
+			{"op":"replace","path":"/b","value":5}
+		]`).Apply(&om); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := json.Marshal(&om)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `{"c":3,"d":1,"b":5}`; string(got) != want {
+			t.Fatalf("got: %s, want: %s", got, want)
+		}
+	})
+
+	t.Run("ordered map without InsertAt", func(t *testing.T) {
+		om := setOnlyMap{}
+		om.Set("a", &ValueWithIndex{Foo: "a"})
+		om.Set("b", &ValueWithIndex{Foo: "b"})
+		om.Set("c", &ValueWithIndex{Foo: "c"})
+
+		if err := mustPatch(t, `[{"op":"move","from":"/a","path":"/c"}]`).Apply(&om); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := collectKeys(om); !slices.Equal(got, []string{"b", "c"}) || om["c"].Foo != "a" {
+			t.Fatalf("got: %v", got)
+		}
+
+		err := mustPatch(t, `[{"op":"move","from":"/b","path":"/d","position":0}]`).Apply(&om)
+		if !errors.Is(err, errors.ErrUnsupported) {
+			t.Fatalf("got: %v", err)
+		}
+
+		if got := collectKeys(om); !slices.Equal(got, []string{"b", "c"}) {
+			t.Fatalf("document changed: %v", got)
+		}
+	})
+}
+
+func TestCreatePatch(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		original, modified, want string
+	}{
+		{`{"a":1,"b":2,"c":3}`, `{"c":3,"a":1,"b":2}`, `[{"op":"move","from":"/c","path":"/c","position":0}]`},
+		{`{"a":1,"b":2}`, `{"b":2,"x":0,"a":1}`, `[{"op":"add","path":"/x","value":0,"position":2},{"op":"move","from":"/a","path":"/a","position":2}]`},
+		{`{"a":1,"b":{"c":1,"d":2}}`, `{"a":1,"b":{"d":3,"c":1}}`, `[{"op":"move","from":"/b/c","path":"/b/c","position":1},{"op":"replace","path":"/b/d","value":3}]`},
+		{`{"a~b":1,"c/d":2}`, `{"c/d":2}`, `[{"op":"remove","path":"/a~0b"}]`},
+		{`{"a":[1,2]}`, `{"a":[2,1]}`, `[{"op":"replace","path":"/a","value":[2,1]}]`},
+		{`{"a":1}`, `{"a":1.0}`, `[]`},
+	} {
+		original, modified := mustNode(t, tc.original), mustNode(t, tc.modified)
+
+		patch, err := ordmap.CreatePatch(original, modified)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := json.Marshal(patch)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(b) != tc.want {
+			t.Fatalf("%s -> %s: got: %s, want: %s", tc.original, tc.modified, b, tc.want)
+		}
+
+		got, err := ordmap.ApplyPatch(original, patch)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.String() != modified.String() && !got.Equal(modified) {
+			t.Fatalf("%s -> %s: patched: %s", tc.original, tc.modified, got)
+		}
+	}
+}
+
+func TestDiffPatch(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewPCG(1, 2))
+
+	for range 200 {
+		a, b := ordmap.OrderedMap[string, int]{}, ordmap.OrderedMap[string, int]{}
+
+		keys := make([]string, 8)
+		for i := range keys {
+			keys[i] = fmt.Sprint(i)
+		}
+
+		r.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+		for _, k := range keys[:r.IntN(len(keys))] {
+			a.Set(k, r.IntN(2))
+		}
+
+		r.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+		for _, k := range keys[:r.IntN(len(keys))] {
+			b.Set(k, r.IntN(2))
+		}
+
+		patch, err := ordmap.DiffPatch("", a, b, func(x, y int) bool { return x == y })
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := ordmap.OrderedMap[string, int]{}
+		for k, v := range a.ByIndex() {
+			got.Set(k, v)
+		}
+
+		if err := patch.Apply(&got); err != nil {
+			t.Fatal(err)
+		}
+
+		if !slices.Equal(collectKeys(got), collectKeys(b)) {
+			t.Fatalf("a: %v, b: %v, patch: %v, got: %v", collectKeys(a), collectKeys(b), patch, collectKeys(got))
+		}
+
+		for k, v := range b.ByIndex() {
+			if gv, _ := got.Get(k); gv != v {
+				t.Fatalf("%s: got: %d, want: %d", k, gv, v)
+			}
+		}
+	}
+
+	t.Run("large", func(t *testing.T) {
+		large, moved := largeMaps()
+
+		patch, err := ordmap.DiffPatch("", large, moved, func(x, y int) bool { return x == y })
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(patch) != 99 {
+			t.Fatalf("got %d operations, want 99", len(patch))
+		}
+
+		if err := patch.Apply(&large); err != nil {
+			t.Fatal(err)
+		}
+
+		if !slices.Equal(collectKeys(large), collectKeys(moved)) {
+			t.Fatal("patch does not reorder the keys")
+		}
+	})
+
+	t.Run("minimal", func(t *testing.T) {
+		a, b := ordmap.OrderedMap[string, int]{}, ordmap.OrderedMap[string, int]{}
+		for _, k := range []string{"a", "b", "c", "d", "e"} {
+			a.Set(k, 0)
+		}
+
+		for _, k := range []string{"b", "c", "d", "e", "a"} {
+			b.Set(k, 0)
+		}
+
+		patch, err := ordmap.DiffPatch("/m", a, b, func(x, y int) bool { return x == y })
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(patch) != 1 || patch[0].Op != "move" || patch[0].Path != "/m/a" || *patch[0].Position != 4 {
+			t.Fatalf("got: %+v", patch)
+		}
+	})
+}
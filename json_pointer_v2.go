@@ -0,0 +1,708 @@
+package ordmap
+
+import (
+	"encoding"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MarkRosemaker/errpath"
+)
+
+// GetPointer returns the value that the JSON pointer (RFC 6901) refers to, e.g. "/paths/~1users/get".
+// It walks through ordered maps, which includes the values of an OrderedMap and of a Node,
+// other maps, slices, structs by the JSON names of their fields, pointers and interfaces.
+// The empty pointer refers to v itself.
+func GetPointer(v any, ptr jsontext.Pointer) (any, error) {
+	tokens, err := pointerTokens(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	rv, err := lookup(reflect.ValueOf(v), tokens)
+	if err != nil || !rv.IsValid() {
+		return nil, err
+	}
+
+	return rv.Interface(), nil
+}
+
+// SetPointer sets the value that the JSON pointer refers to.
+// A new key is added at the end of the order of a map with a Set method such as OrderedMap,
+// while an existing key keeps its position. For a slice, the index must exist
+// or be "-" to append an element. A value that cannot be assigned is converted by encoding it as JSON
+// and decoding it into the type at the pointer.
+// Pass a pointer as v so that values without a reference, e.g. a slice that grows, can be replaced.
+// If v is not a pointer and the change would have to replace it, e.g. a field of a struct,
+// SetPointer returns ErrInvalidPointer.
+func SetPointer(v any, ptr jsontext.Pointer, val any) error {
+	return applyPointer(v, ptr, val, func(c reflect.Value, tok string, val reflect.Value) (reflect.Value, error) {
+		if c.Kind() == reflect.Slice {
+			i, err := parseIndex(tok, c.Len(), tok == "-")
+			if err != nil {
+				return c, err
+			}
+
+			if i == c.Len() {
+				return reflect.Append(c, val), nil
+			}
+		}
+
+		return putChild(c, tok, val)
+	})
+}
+
+// AddPointer adds the value at the JSON pointer like the "add" operation of JSON Patch (RFC 6902).
+// Unlike SetPointer, it inserts an element into a slice at the index, which may be the length or "-" to append.
+// For maps and structs, it is the same as SetPointer. Like SetPointer, it needs a pointer to change a struct or
+// to replace a slice.
+func AddPointer(v any, ptr jsontext.Pointer, val any) error {
+	return applyPointer(v, ptr, val, func(c reflect.Value, tok string, val reflect.Value) (reflect.Value, error) {
+		if c.Kind() != reflect.Slice {
+			return putChild(c, tok, val)
+		}
+
+		i, err := parseIndex(tok, c.Len(), true)
+		if err != nil {
+			return c, err
+		}
+
+		return insertElem(c, i, val), nil
+	})
+}
+
+// InsertPointer sets the value of the key that the JSON pointer refers to
+// at the given zero-based position of the ordered map that contains it, which must have an InsertAt method
+// such as OrderedMap. If the key already exists, it is moved to that position.
+func InsertPointer(v any, ptr jsontext.Pointer, pos int, val any) error {
+	return applyPointer(v, ptr, val, func(c reflect.Value, tok string, val reflect.Value) (reflect.Value, error) {
+		if c.Kind() != reflect.Map {
+			return c, fmt.Errorf("%w: cannot insert at a position of %s", errors.ErrUnsupported, c.Type())
+		}
+
+		key, err := parseKey(c.Type().Key(), tok)
+		if err != nil {
+			return c, err
+		}
+
+		m, insertAt := mapMethod(c, "InsertAt", reflect.TypeFor[int](), key.Type(), val.Type())
+		if !insertAt.IsValid() {
+			return c, fmt.Errorf("%w: %s has no InsertAt method", errors.ErrUnsupported, c.Type())
+		}
+
+		if err, _ := insertAt.Call([]reflect.Value{reflect.ValueOf(pos), key, val})[0].Interface().(error); err != nil {
+			return c, err
+		}
+
+		return m.Elem(), nil
+	})
+}
+
+// DeletePointer removes the value that the JSON pointer refers to.
+// It deletes a key from a map and an element from a slice, and sets a struct field to its zero value.
+// Like SetPointer, it needs a pointer to change a struct or to replace a slice.
+func DeletePointer(v any, ptr jsontext.Pointer) error {
+	tokens, err := pointerTokens(ptr)
+	if err != nil {
+		return err
+	}
+
+	if len(tokens) == 0 {
+		return ErrInvalidPointer // the root cannot be removed
+	}
+
+	return applyRoot(reflect.ValueOf(v), tokens, func(c reflect.Value, tok string) (reflect.Value, error) {
+		switch c.Kind() {
+		case reflect.Map:
+			if _, err := getChild(c, tok); err != nil {
+				return c, err
+			}
+
+			key, _ := parseKey(c.Type().Key(), tok)
+			c.SetMapIndex(key, reflect.Value{})
+
+			return c, nil
+		case reflect.Slice:
+			i, err := parseIndex(tok, c.Len(), false)
+			if err != nil {
+				return c, err
+			}
+
+			elems := reflect.MakeSlice(c.Type(), 0, c.Len()-1)
+			elems = reflect.AppendSlice(elems, c.Slice(0, i))
+
+			return reflect.AppendSlice(elems, c.Slice(i+1, c.Len())), nil
+		default: // struct
+			f, err := getChild(c, tok)
+			if err != nil {
+				return c, err
+			}
+
+			return putChild(c, tok, reflect.Zero(f.Type()))
+		}
+	})
+}
+
+// applyPointer converts the value to the type at the JSON pointer and lets set put it into its container.
+func applyPointer(
+	v any, ptr jsontext.Pointer, val any,
+	set func(c reflect.Value, tok string, val reflect.Value) (reflect.Value, error),
+) error {
+	tokens, err := pointerTokens(ptr)
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+
+	// replace the root
+	if len(tokens) == 0 {
+		if rv.Kind() != reflect.Pointer || rv.IsNil() {
+			return ErrInvalidPointer
+		}
+
+		cv, err := convertValue(val, rv.Elem().Type())
+		if err != nil {
+			return err
+		}
+
+		rv.Elem().Set(cv)
+
+		return nil
+	}
+
+	return applyRoot(rv, tokens, func(c reflect.Value, tok string) (reflect.Value, error) {
+		cv, err := convertValue(val, childType(c, tok))
+		if err != nil {
+			return c, err
+		}
+
+		return set(c, tok, cv)
+	})
+}
+
+// applyRoot applies op like apply and returns an error if the change is lost
+// because v is not a pointer and would have to be replaced.
+func applyRoot(
+	v reflect.Value, tokens []string,
+	op func(c reflect.Value, tok string) (reflect.Value, error),
+) error {
+	changed, err := apply(v, tokens, op)
+	if err != nil {
+		return err
+	}
+
+	if v.Kind() != reflect.Pointer && !sameValue(v, changed) {
+		return fmt.Errorf("%w: cannot change %s that is not passed as a pointer", ErrInvalidPointer, v.Type())
+	}
+
+	return nil
+}
+
+// sameValue reports whether b is a shallow copy of a, i.e. whether they share their maps, slices and pointers
+// and their other values are equal, so that a change of b that is done in place can be seen through a.
+func sameValue(a, b reflect.Value) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Map, reflect.Pointer, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	case reflect.Slice:
+		return a.Len() == b.Len() && a.Pointer() == b.Pointer()
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+
+		return sameValue(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := range a.NumField() {
+			if !sameValue(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+
+		return true
+	case reflect.Array:
+		for i := range a.Len() {
+			if !sameValue(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+
+		return true
+	case reflect.Float32, reflect.Float64:
+		return math.Float64bits(a.Float()) == math.Float64bits(b.Float())
+	default:
+		return a.Equal(b)
+	}
+}
+
+// pointerTokens returns the unescaped reference tokens of a JSON pointer.
+func pointerTokens(ptr jsontext.Pointer) ([]string, error) {
+	if !ptr.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPointer, ptr)
+	}
+
+	return slices.Collect(ptr.Tokens()), nil
+}
+
+// lookup returns the value that the tokens refer to.
+func lookup(v reflect.Value, tokens []string) (reflect.Value, error) {
+	if len(tokens) == 0 {
+		return v, nil
+	}
+
+	c, _, err := container(v)
+	if err != nil {
+		return v, err
+	}
+
+	child, err := getChild(c, tokens[0])
+	if err == nil {
+		child, err = lookup(child, tokens[1:])
+	}
+
+	if err != nil {
+		return v, wrapPointerErr(c, tokens[0], err)
+	}
+
+	return child, nil
+}
+
+// apply lets op change the container of the last token and puts every changed container back into its parent.
+// It returns the changed value, which is the same as v for values with a reference, e.g. maps.
+func apply(
+	v reflect.Value, tokens []string,
+	op func(c reflect.Value, tok string) (reflect.Value, error),
+) (reflect.Value, error) {
+	c, rewrap, err := container(v)
+	if err != nil {
+		return v, err
+	}
+
+	var changed reflect.Value
+	if len(tokens) == 1 {
+		changed, err = op(c, tokens[0])
+	} else {
+		var child reflect.Value
+		if child, err = getChild(c, tokens[0]); err == nil {
+			if child, err = apply(child, tokens[1:], op); err == nil {
+				changed, err = putChild(c, tokens[0], child)
+			}
+		}
+	}
+
+	if err != nil {
+		return v, wrapPointerErr(c, tokens[0], err)
+	}
+
+	return rewrap(changed), nil
+}
+
+// container returns the map, slice or struct that a value holds and a function that puts a changed one back.
+func container(v reflect.Value) (reflect.Value, func(reflect.Value) reflect.Value, error) {
+	switch {
+	case !v.IsValid():
+		return v, nil, ErrNotContainer
+	case v.Type() == reflect.TypeFor[Node]():
+		n := v.Interface().(Node)
+		switch n.kind {
+		case '{':
+			return reflect.ValueOf(n.object), func(c reflect.Value) reflect.Value {
+				return reflect.ValueOf(NewObject(c.Interface().(Object)))
+			}, nil
+		case '[':
+			return reflect.ValueOf(n.array), func(c reflect.Value) reflect.Value {
+				return reflect.ValueOf(NewArray(c.Interface().(Array)...))
+			}, nil
+		default:
+			return v, nil, ErrNotContainer
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v, nil, ErrNotContainer
+		}
+
+		c, rewrap, err := container(v.Elem())
+		return c, func(c reflect.Value) reflect.Value {
+			iv := reflect.New(v.Type()).Elem()
+			iv.Set(rewrap(c))
+
+			return iv
+		}, err
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v, nil, ErrNotContainer
+		}
+
+		c, rewrap, err := container(v.Elem())
+		return c, func(c reflect.Value) reflect.Value {
+			v.Elem().Set(rewrap(c))
+			return v
+		}, err
+	case reflect.Map, reflect.Slice, reflect.Struct:
+		return v, func(c reflect.Value) reflect.Value { return c }, nil
+	default:
+		return v, nil, ErrNotContainer
+	}
+}
+
+// getChild returns the member, element or field of the container that the token refers to.
+// For an OrderedMap, it returns the value without its index.
+func getChild(c reflect.Value, tok string) (reflect.Value, error) {
+	switch c.Kind() {
+	case reflect.Map:
+		key, err := parseKey(c.Type().Key(), tok)
+		if err != nil {
+			return c, err
+		}
+
+		child := c.MapIndex(key)
+		if !child.IsValid() {
+			return c, ErrKeyNotFound
+		}
+
+		if isValueType(child.Type()) {
+			return child.Field(0), nil
+		}
+
+		return child, nil
+	case reflect.Slice:
+		i, err := parseIndex(tok, c.Len(), false)
+		if err != nil {
+			return c, err
+		}
+
+		return c.Index(i), nil
+	default: // struct
+		idx, ok := fieldIndex(c.Type(), tok)
+		if !ok {
+			return c, ErrKeyNotFound
+		}
+
+		return c.FieldByIndex(idx), nil
+	}
+}
+
+// childType returns the type of the member, element or field of the container that the token refers to,
+// which need not exist yet.
+func childType(c reflect.Value, tok string) reflect.Type {
+	switch c.Kind() {
+	case reflect.Map:
+		if elem := c.Type().Elem(); isValueType(elem) {
+			return elem.Field(0).Type
+		}
+
+		return c.Type().Elem()
+	case reflect.Slice:
+		return c.Type().Elem()
+	default: // struct
+		if idx, ok := fieldIndex(c.Type(), tok); ok {
+			return c.Type().FieldByIndex(idx).Type
+		}
+
+		return nil
+	}
+}
+
+// putChild sets the existing member, element or field of the container that the token refers to,
+// or adds a new member to a map, and returns the changed container.
+func putChild(c reflect.Value, tok string, val reflect.Value) (reflect.Value, error) {
+	switch c.Kind() {
+	case reflect.Map:
+		key, err := parseKey(c.Type().Key(), tok)
+		if err != nil {
+			return c, err
+		}
+
+		// use the Set method to keep the position of an existing key and add a new one at the end
+		if m, set := mapMethod(c, "Set", key.Type(), val.Type()); set.IsValid() {
+			set.Call([]reflect.Value{key, val})
+			return m.Elem(), nil
+		}
+
+		if c.IsNil() {
+			c = reflect.MakeMap(c.Type())
+		}
+
+		if elem := c.Type().Elem(); isValueType(elem) {
+			// keep the index of an existing key and add a new key at the end
+			wrapped := reflect.New(elem).Elem()
+			if old := c.MapIndex(key); old.IsValid() {
+				wrapped.Set(old)
+			} else {
+				wrapped.Set(reflect.ValueOf(wrapped.Interface().(indexed).withIndex(highestValueIndex(c) + 1)))
+			}
+
+			wrapped.Field(0).Set(val)
+			val = wrapped
+		}
+
+		c.SetMapIndex(key, val)
+
+		return c, nil
+	case reflect.Slice:
+		i, err := parseIndex(tok, c.Len(), false)
+		if err != nil {
+			return c, err
+		}
+
+		c.Index(i).Set(val)
+
+		return c, nil
+	default: // struct
+		idx, ok := fieldIndex(c.Type(), tok)
+		if !ok {
+			return c, ErrKeyNotFound
+		}
+
+		s := reflect.New(c.Type()).Elem()
+		s.Set(c)
+		s.FieldByIndex(idx).Set(val)
+
+		return s, nil
+	}
+}
+
+// highestValueIndex returns the highest index of the values of a map of Value.
+func highestValueIndex(c reflect.Value) int {
+	highest := 0
+	for it := c.MapRange(); it.Next(); {
+		highest = max(highest, it.Value().Interface().(indexed).index())
+	}
+
+	return highest
+}
+
+// insertElem returns the slice with the value inserted at the index.
+func insertElem(c reflect.Value, i int, val reflect.Value) reflect.Value {
+	elems := reflect.MakeSlice(c.Type(), 0, c.Len()+1)
+	elems = reflect.AppendSlice(elems, c.Slice(0, i))
+	elems = reflect.Append(elems, val)
+
+	return reflect.AppendSlice(elems, c.Slice(i, c.Len()))
+}
+
+// mapMethod returns a pointer to a copy of the map and its method with the given name
+// if it has the given parameters, or an invalid value otherwise.
+func mapMethod(c reflect.Value, name string, in ...reflect.Type) (reflect.Value, reflect.Value) {
+	m := reflect.New(c.Type())
+	m.Elem().Set(c)
+
+	method := m.MethodByName(name)
+	if !method.IsValid() || method.Type().NumIn() != len(in) {
+		return m, reflect.Value{}
+	}
+
+	for i, t := range in {
+		if method.Type().In(i) != t {
+			return m, reflect.Value{}
+		}
+	}
+
+	return m, method
+}
+
+// wrapPointerErr adds the token to the path of the error.
+func wrapPointerErr(c reflect.Value, tok string, err error) error {
+	switch c.Kind() {
+	case reflect.Slice:
+		if i, perr := strconv.Atoi(tok); perr == nil {
+			return &errpath.ErrIndex{Index: i, Err: err}
+		}
+	case reflect.Struct:
+		return &errpath.ErrField{Field: tok, Err: err}
+	}
+
+	return &errpath.ErrKey{Key: tok, Err: err}
+}
+
+// parseIndex parses an array index of a JSON pointer, which must be less than n or, if allowEnd is set, equal to n.
+// The token "-" refers to the end of the array.
+func parseIndex(tok string, n int, allowEnd bool) (int, error) {
+	i := n
+	if tok != "-" {
+		// leading zeros and signs are not allowed
+		if tok == "" || (len(tok) > 1 && tok[0] == '0') || strings.TrimLeft(tok, "0123456789") != "" {
+			return 0, ErrInvalidIndex
+		}
+
+		var err error
+		if i, err = strconv.Atoi(tok); err != nil {
+			return 0, ErrInvalidIndex
+		}
+	}
+
+	if i > n || (i == n && !allowEnd) {
+		return 0, ErrOutOfRange
+	}
+
+	return i, nil
+}
+
+// parseKey parses a token of a JSON pointer into a map key following the rules for JSON object names.
+func parseKey(t reflect.Type, tok string) (reflect.Value, error) {
+	key := reflect.New(t)
+	if u, ok := key.Interface().(encoding.TextUnmarshaler); ok {
+		return key.Elem(), u.UnmarshalText([]byte(tok))
+	}
+
+	var err error
+
+	key = key.Elem()
+	switch t.Kind() {
+	case reflect.String:
+		key.SetString(tok)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(tok, 10, t.Bits())
+		key.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		u, err = strconv.ParseUint(tok, 10, t.Bits())
+		key.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(tok, t.Bits())
+		key.SetFloat(f)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(tok)
+		key.SetBool(b)
+	default:
+		err = fmt.Errorf("%w: map key of type %s", errors.ErrUnsupported, t)
+	}
+
+	return key, err
+}
+
+// fieldIndex returns the index of the struct field with the given JSON name, also looking into embedded structs.
+func fieldIndex(t reflect.Type, name string) ([]int, bool) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		tagName, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tagName == "-" && f.Tag.Get("json") == "-" {
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if f.Anonymous && tagName == "" && f.Type.Kind() == reflect.Struct {
+			if idx, ok := fieldIndex(f.Type, name); ok {
+				return append([]int{i}, idx...), true
+			}
+
+			continue
+		}
+
+		if tagName == "" {
+			tagName = f.Name
+		}
+
+		if tagName == name {
+			return []int{i}, true
+		}
+	}
+
+	return nil, false
+}
+
+// isValueType reports whether the type is a Value of this package, whose index is kept when it is changed.
+// A struct that embeds a Value has its methods too, but not its fields.
+func isValueType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.Implements(reflect.TypeFor[indexed]()) &&
+		t.NumField() == 2 && t.Field(1).Name == "idx"
+}
+
+// convertValue returns the value as the given type, converting it through JSON if it cannot be assigned.
+func convertValue(val any, t reflect.Type) (reflect.Value, error) {
+	if t == nil {
+		return reflect.Value{}, ErrKeyNotFound
+	}
+
+	rv := reflect.ValueOf(val)
+	if !rv.IsValid() {
+		return reflect.Zero(t), nil
+	}
+
+	if rv.Type().AssignableTo(t) {
+		cv := reflect.New(t).Elem()
+		cv.Set(rv)
+
+		return cv, nil
+	}
+
+	b, err := json.Marshal(val)
+	if err != nil {
+		return rv, err
+	}
+
+	// keep the order of objects that are decoded into an interface
+	cv := reflect.New(t)
+	if err := json.Unmarshal(b, cv.Interface(), OrderedObjects()); err != nil {
+		return rv, err
+	}
+
+	return cv.Elem(), nil
+}
+
+// keyOrder returns the keys of the map that the JSON pointer refers to as reference tokens in order,
+// and false if it is not a map with a ByIndex and an InsertAt method.
+func keyOrder(v any, ptr jsontext.Pointer) ([]string, bool, error) {
+	tokens, err := pointerTokens(ptr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rv, err := lookup(reflect.ValueOf(v), tokens)
+	if err != nil {
+		return nil, false, err
+	}
+
+	c, _, err := container(rv)
+	if err != nil || c.Kind() != reflect.Map {
+		return nil, false, err
+	}
+
+	_, byIndex := mapMethod(c, "ByIndex")
+	_, insertAt := mapMethod(c, "InsertAt", reflect.TypeFor[int](), c.Type().Key(), childType(c, ""))
+	if !byIndex.IsValid() || !insertAt.IsValid() {
+		return nil, false, nil
+	}
+
+	var keys []string
+
+	seq := byIndex.Call(nil)[0]
+	seq.Call([]reflect.Value{reflect.MakeFunc(seq.Type().In(0), func(args []reflect.Value) []reflect.Value {
+		keys = append(keys, keyToken(args[0]))
+		return []reflect.Value{reflect.ValueOf(true)}
+	})})
+
+	return keys, true, nil
+}
+
+// keyToken returns the reference token of a map key, following the rules for JSON object names.
+func keyToken(key reflect.Value) string {
+	if m, ok := key.Interface().(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	return fmt.Sprint(key.Interface())
+}
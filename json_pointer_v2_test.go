@@ -0,0 +1,357 @@
+package ordmap_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+type spec struct {
+	Info  info                                `json:"info"`
+	Paths ordmap.OrderedMap[string, pathItem] `json:"paths"`
+}
+
+type info struct {
+	Title string `json:"title"`
+}
+
+type pathItem struct {
+	Get  *operation `json:"get,omitempty"`
+	Tags []string   `json:"tags,omitempty"`
+}
+
+func TestPointer(t *testing.T) {
+	t.Parallel()
+
+	newSpec := func(t *testing.T) *spec {
+		t.Helper()
+
+		s := &spec{}
+		if err := json.Unmarshal([]byte(`{
+			"info": {"title": "API"},
+			"paths": {
+				"/users": {"get": {"summary": "list users"}, "tags": ["a", "c"]},
+				"/pets": {}
+			}
+		}`), s); err != nil {
+			t.Fatal(err)
+		}
+
+		return s
+	}
+
+	t.Run("get", func(t *testing.T) {
+		s := newSpec(t)
+
+		for ptr, want := range map[string]any{
+			"/info/title":                "API",
+			"/paths/~1users/get/summary": "list users",
+			"/paths/~1users/tags/1":      "c",
+		} {
+			got, err := ordmap.GetPointer(s, jsontext.Pointer(ptr))
+			if err != nil {
+				t.Fatalf("%s: %v", ptr, err)
+			}
+
+			if got != want {
+				t.Fatalf("%s: got: %v, want: %v", ptr, got, want)
+			}
+		}
+
+		got, err := ordmap.GetPointer(s, "")
+		if err != nil || got != s {
+			t.Fatalf("got: %v, %v", got, err)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		s := newSpec(t)
+
+		if err := ordmap.SetPointer(s, "/paths/~1users/get/summary", "all users"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.SetPointer(s, "/paths/~1users/tags/-", "d"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.SetPointer(s, "/paths/~1users/tags/0", "b"); err != nil {
+			t.Fatal(err)
+		}
+
+		// a new key is added at the end, an existing one keeps its position
+		if err := ordmap.SetPointer(s, "/paths/~1stores", map[string]any{"tags": []string{"x"}}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.SetPointer(s, "/paths/~1pets", pathItem{Tags: []string{"p"}}); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		const want = `{"info":{"title":"API"},"paths":{` +
+			`"/users":{"get":{"summary":"all users"},"tags":["b","c","d"]},` +
+			`"/pets":{"tags":["p"]},"/stores":{"tags":["x"]}}}`
+		if string(got) != want {
+			t.Fatalf("got: %s, want: %s", got, want)
+		}
+	})
+
+	t.Run("add and insert", func(t *testing.T) {
+		s := newSpec(t)
+
+		if err := ordmap.AddPointer(s, "/paths/~1users/tags/1", "b"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.AddPointer(s, "/paths/~1users/tags/3", "d"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.InsertPointer(s, "/paths/~1stores", 1, pathItem{}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.InsertPointer(s, "/paths/~1pets", 0, pathItem{}); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := collectKeys(s.Paths), []string{"/pets", "/users", "/stores"}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+
+		users, _ := s.Paths.Get("/users")
+		if want := []string{"a", "b", "c", "d"}; !slices.Equal(users.Tags, want) {
+			t.Fatalf("got: %v, want: %v", users.Tags, want)
+		}
+
+		err := ordmap.InsertPointer(s, "/paths/~1users/tags/0", 0, "x")
+		if !errors.Is(err, errors.ErrUnsupported) {
+			t.Fatalf("got: %v", err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		s := newSpec(t)
+
+		for _, ptr := range []string{"/paths/~1users/tags/0", "/paths/~1pets", "/info/title"} {
+			if err := ordmap.DeletePointer(s, jsontext.Pointer(ptr)); err != nil {
+				t.Fatalf("%s: %v", ptr, err)
+			}
+		}
+
+		got, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		const want = `{"info":{"title":""},"paths":{"/users":{"get":{"summary":"list users"},"tags":["c"]}}}`
+		if string(got) != want {
+			t.Fatalf("got: %s, want: %s", got, want)
+		}
+
+		if err := ordmap.DeletePointer(s, ""); !errors.Is(err, ordmap.ErrInvalidPointer) {
+			t.Fatalf("got: %v", err)
+		}
+	})
+
+	t.Run("arbitrary JSON", func(t *testing.T) {
+		var doc any
+		if err := json.Unmarshal([]byte(`{"b":{"y":[1,{"z":true}],"x":2},"a":null}`), &doc,
+			ordmap.OrderedObjects()); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := ordmap.GetPointer(doc, "/b/y/1/z")
+		if err != nil || got != true {
+			t.Fatalf("got: %v, %v", got, err)
+		}
+
+		if err := ordmap.SetPointer(&doc, "/b/y/1/w", "new"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.AddPointer(&doc, "/b/y/0", 0); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.DeletePointer(&doc, "/b/x"); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `{"b":{"y":[0,1,{"z":true,"w":"new"}]},"a":null}`; string(b) != want {
+			t.Fatalf("got: %s, want: %s", b, want)
+		}
+	})
+
+	t.Run("node", func(t *testing.T) {
+		var doc ordmap.Node
+		if err := json.Unmarshal([]byte(`{"b":[1,2],"a":{"x":1}}`), &doc); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.SetPointer(&doc, "/a/y", ordmap.NewString("y")); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.AddPointer(&doc, "/b/1", 1.5); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.InsertPointer(&doc, "/c", 0, []int{3}); err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `{"c":[3],"b":[1,1.5,2],"a":{"x":1,"y":"y"}}`; doc.String() != want {
+			t.Fatalf("got: %s, want: %s", doc.String(), want)
+		}
+
+		got, err := ordmap.GetPointer(doc, "/a/x")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n, ok := got.(ordmap.Node); !ok || n.String() != `1` {
+			t.Fatalf("got: %v", got)
+		}
+	})
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		om := UserDefinedOrderedMap{}
+		om.Set("foo", &ValueWithIndex{Foo: "a"})
+
+		if err := ordmap.SetPointer(om, "/foo/bar", 3); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.SetPointer(om, "/baz", map[string]any{"foo": "c"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ordmap.InsertPointer(&om, "/bar", 1, &ValueWithIndex{Foo: "b"}); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := json.Marshal(&om)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `{"foo":{"foo":"a","bar":3},"bar":{"foo":"b","bar":0},"baz":{"foo":"c","bar":0}}`; string(got) != want {
+			t.Fatalf("got: %s, want: %s", got, want)
+		}
+	})
+
+	t.Run("map of values without Set", func(t *testing.T) {
+		type valueMap map[string]ordmap.Value[int]
+
+		m := valueMap{}
+		for _, k := range []string{"c", "a", "b"} {
+			if err := ordmap.SetPointer(m, jsontext.Pointer("/"+k), 1); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := ordmap.SetPointer(m, "/c", 2); err != nil {
+			t.Fatal(err)
+		}
+
+		om := ordmap.OrderedMap[string, int](m)
+		if got := collectKeys(om); !slices.Equal(got, []string{"c", "a", "b"}) {
+			t.Fatalf("got: %v", got)
+		}
+
+		if v, _ := om.Get("c"); v != 2 {
+			t.Fatalf("got: %d", v)
+		}
+
+		if err := om.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		s := newSpec(t)
+
+		_, err := ordmap.GetPointer(s, "/paths/~1users/get/summary/x")
+		if !errors.Is(err, ordmap.ErrNotContainer) {
+			t.Fatalf("got: %v", err)
+		}
+
+		_, err = ordmap.GetPointer(s, "/paths/~1users/tags/2")
+		if !errors.Is(err, ordmap.ErrOutOfRange) {
+			t.Fatalf("got: %v", err)
+		}
+
+		if want := `paths["/users"].tags[2]: position out of range`; err.Error() != want {
+			t.Fatalf("got: %v, want: %v", err, want)
+		}
+
+		_, err = ordmap.GetPointer(s, "/paths/~1nope/get")
+		if errKey := errAs[errpath.ErrKey](t, err); errKey.Key != "/nope" || !errors.Is(err, ordmap.ErrKeyNotFound) {
+			t.Fatalf("got: %v", err)
+		}
+
+		for _, ptr := range []string{"/paths/~1users/tags/01", "/paths/~1users/tags/-1", "/paths/~1users/tags/x"} {
+			if _, err := ordmap.GetPointer(s, jsontext.Pointer(ptr)); !errors.Is(err, ordmap.ErrInvalidIndex) {
+				t.Fatalf("%s: got: %v", ptr, err)
+			}
+		}
+
+		if err := ordmap.SetPointer(s, "/info/nope", 1); !errors.Is(err, ordmap.ErrKeyNotFound) {
+			t.Fatalf("got: %v", err)
+		}
+
+		if err := ordmap.SetPointer(s, "/info/title", []int{1}); err == nil {
+			t.Fatal("expected error")
+		}
+
+		if _, err := ordmap.GetPointer(s, "paths"); !errors.Is(err, ordmap.ErrInvalidPointer) {
+			t.Fatalf("got: %v", err)
+		}
+	})
+
+	t.Run("not a pointer", func(t *testing.T) {
+		s := newSpec(t)
+
+		if err := ordmap.SetPointer(*s, "/info/title", "x"); !errors.Is(err, ordmap.ErrInvalidPointer) {
+			t.Fatalf("got: %v", err)
+		}
+
+		if err := ordmap.DeletePointer(*s, "/info"); !errors.Is(err, ordmap.ErrInvalidPointer) {
+			t.Fatalf("got: %v", err)
+		}
+
+		if err := ordmap.AddPointer([]int{1, 2}, "/-", 3); !errors.Is(err, ordmap.ErrInvalidPointer) {
+			t.Fatalf("got: %v", err)
+		}
+
+		// changes inside a map or in place in a slice are not lost
+		if err := ordmap.SetPointer(*s, "/paths/~1pets/tags", []string{"x"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if pets, _ := s.Paths.Get("/pets"); !slices.Equal(pets.Tags, []string{"x"}) {
+			t.Fatalf("got: %v", pets.Tags)
+		}
+
+		elems := []int{1, 2}
+		if err := ordmap.SetPointer(elems, "/0", 3); err != nil || elems[0] != 3 {
+			t.Fatalf("got: %v, %v", elems, err)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package ordmap
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+)
+
+// UnknownMembers decodes the unknown members of a JSON object into an ordered map.
+//
+// json v2 captures the members of a JSON object that match no struct field in an embedded fallback field,
+// which must be a jsontext.Value or a Go map without JSON methods. Only a jsontext.Value keeps the order,
+// so capture the unknown members in one and use this function to work with them as an ordered map:
+//
+//	type Operation struct {
+//		Summary    string         `json:"summary"`
+//		Extensions jsontext.Value `json:",embed"`
+//	}
+//
+//	exts, err := ordmap.UnknownMembers[any](op.Extensions)
+//
+// An empty value, i.e. a struct without unknown members, results in a nil map.
+func UnknownMembers[V any](raw jsontext.Value, opts ...json.Options) (OrderedMap[string, V], error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var om OrderedMap[string, V]
+	if err := json.Unmarshal(raw, &om, opts...); err != nil {
+		return nil, err
+	}
+
+	return om, nil
+}
+
+// MarshalUnknownMembers encodes an ordered map in order so it can be set as the unknown members of a struct,
+// which json v2 then encodes after the known fields. See UnknownMembers.
+// An empty map results in an empty value, so the struct is encoded without unknown members.
+func MarshalUnknownMembers[V any](om OrderedMap[string, V], opts ...json.Options) (jsontext.Value, error) {
+	if len(om) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(&om, opts...)
+}
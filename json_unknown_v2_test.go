@@ -0,0 +1,98 @@
+package ordmap_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/ordmap"
+)
+
+type operation struct {
+	Summary     string         `json:"summary"`
+	OperationID string         `json:"operationId,omitempty"`
+	Extensions  jsontext.Value `json:",embed"`
+}
+
+func TestUnknownMembers(t *testing.T) {
+	t.Parallel()
+
+	const data = `{"x-zeta":1,"summary":"list users","x-alpha":{"b":2,"a":1},"x-middle":"m"}`
+
+	var op operation
+	if err := json.Unmarshal([]byte(data), &op); err != nil {
+		t.Fatal(err)
+	}
+
+	exts, err := ordmap.UnknownMembers[any](op.Extensions, ordmap.OrderedObjects())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := collectKeys(exts), []string{"x-zeta", "x-alpha", "x-middle"}; !slices.Equal(got, want) {
+		t.Fatalf("got: %v, want: %v", got, want)
+	}
+
+	// change the extensions and set them again
+	exts.Delete("x-middle")
+	exts.Set("x-new", true)
+	_ = exts.MoveToFront("x-alpha")
+
+	if op.Extensions, err = ordmap.MarshalUnknownMembers(exts); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"summary":"list users","x-alpha":{"b":2,"a":1},"x-zeta":1,"x-new":true}`; string(got) != want {
+		t.Fatalf("got: %s, want: %s", got, want)
+	}
+
+	t.Run("none", func(t *testing.T) {
+		var op operation
+		if err := json.Unmarshal([]byte(`{"summary":"s"}`), &op); err != nil {
+			t.Fatal(err)
+		}
+
+		exts, err := ordmap.UnknownMembers[jsontext.Value](op.Extensions)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exts != nil {
+			t.Fatalf("got: %v, want nil", exts)
+		}
+
+		if op.Extensions, err = ordmap.MarshalUnknownMembers(exts); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := json.Marshal(op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := `{"summary":"s"}`; string(got) != want {
+			t.Fatalf("got: %s, want: %s", got, want)
+		}
+	})
+
+	t.Run("raw values", func(t *testing.T) {
+		exts, err := ordmap.UnknownMembers[jsontext.Value](jsontext.Value(`{"x-b":[1, 2],"x-a":null}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v, _ := exts.Get("x-b"); string(v) != `[1, 2]` {
+			t.Fatalf("got: %s", v)
+		}
+
+		if _, err := ordmap.UnknownMembers[int](jsontext.Value(`{"x-a":"a"}`)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json/jsontext"
 	"encoding/json/v2"
 	"fmt"
+	"reflect"
+	"strconv"
 
 	"github.com/MarkRosemaker/errpath"
 )
@@ -11,22 +13,23 @@ import (
 var _ json.UnmarshalerFrom = (*OrderedMap[string, any])(nil)
 
 // UnmarshalJSONFrom unmarshals the key-value pairs in order and sets the indices.
+// Duplicate keys are handled as chosen with WithDuplicateKeys.
 func (om *OrderedMap[K, V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
 	return UnmarshalJSONFrom(om, dec, setIndex)
 }
 
 // UnmarshalJSONFrom is a helper function to unmarshal an ordered map setting the indices in order.
+// Duplicate keys are handled as chosen with WithDuplicateKeys.
 func UnmarshalJSONFrom[M ~map[K]R, K comparable, R any](
 	m *M, dec *jsontext.Decoder,
 	setIndex func(R, int) R,
 ) error {
-	tkn, err := dec.ReadToken()
-	if err != nil {
-		return err
-	}
+	if null, err := beginObject(dec); err != nil || null {
+		if null {
+			*m = nil
+		}
 
-	if tkn.Kind() != '{' {
-		return fmt.Errorf("expected {, got %s", tkn.Kind())
+		return err
 	}
 
 	// create the map
@@ -34,6 +37,62 @@ func UnmarshalJSONFrom[M ~map[K]R, K comparable, R any](
 
 	i := 1 // start at 1 to avoid confusion with zero values
 
+	// remember the index of each key if duplicate keys are not simply overwritten
+	dup := duplicateKeys(dec.Options())
+	var indices map[K]int
+	if dup != DuplicateKeysLastAtLast {
+		indices = map[K]int{}
+	}
+
+	return decodeMembers(dec, func(key K) error {
+		idx, exists := indices[key]
+		if exists {
+			switch dup {
+			case DuplicateKeysReject:
+				return ErrDuplicateKey
+			case DuplicateKeysFirst:
+				return dec.SkipValue()
+			}
+		}
+
+		var v R
+		if err := json.UnmarshalDecode(dec, &v, dec.Options()); err != nil {
+			return err
+		}
+
+		if exists { // i.e. DuplicateKeysLastAtFirst
+			(*m)[key] = setIndex(v, idx)
+			return nil
+		}
+
+		// set the variable in the map with the proper index
+		(*m)[key] = setIndex(v, i)
+		if indices != nil {
+			indices[key] = i
+		}
+
+		i++
+
+		return nil
+	})
+}
+
+// beginObject reads the start of a JSON object or reports that the value is JSON null, which it consumes.
+// Like json v2 does for Go maps, it reports any other kind of JSON value as a *json.SemanticError,
+// which the json package completes with the type, offset and path.
+func beginObject(dec *jsontext.Decoder) (null bool, err error) {
+	switch kind := dec.PeekKind(); kind {
+	case '{', 'n', 0: // zero means invalid or no input, which ReadToken reports
+		_, err := dec.ReadToken()
+		return kind == 'n', err
+	default:
+		return false, &json.SemanticError{JSONKind: kind}
+	}
+}
+
+// decodeMembers decodes the key of each member of a JSON object and lets decodeValue decode the value.
+// The start of the object must have been read already.
+func decodeMembers[K comparable](dec *jsontext.Decoder, decodeValue func(K) error) error {
 	for {
 		// check if we reached the end of the object
 		if dec.PeekKind() == '}' {
@@ -41,18 +100,38 @@ func UnmarshalJSONFrom[M ~map[K]R, K comparable, R any](
 			return err
 		}
 
-		var key K
-		if err := json.UnmarshalDecode(dec, &key, dec.Options()); err != nil {
+		key, err := unmarshalKey[K](dec)
+		if err != nil {
 			return err
 		}
 
-		var v R
-		if err := json.UnmarshalDecode(dec, &v, dec.Options()); err != nil {
+		if err := decodeValue(key); err != nil {
 			return &errpath.ErrKey{Key: fmt.Sprint(key), Err: err}
 		}
+	}
+}
+
+// unmarshalKey decodes a JSON object name into a key following the rules of json v2 for Go map keys,
+// which parses strings, numbers and text unmarshalers from JSON strings.
+// In addition, booleans are parsed from "true" and "false".
+func unmarshalKey[K comparable](dec *jsontext.Decoder) (K, error) {
+	var key K
+	if rv := reflect.ValueOf(&key).Elem(); rv.Kind() == reflect.Bool && !isTextKey[K]() {
+		tkn, err := dec.ReadToken()
+		if err != nil {
+			return key, err
+		}
 
-		// set the variable in the map with the proper index
-		(*m)[K(key)] = setIndex(v, i)
-		i++
+		b, err := strconv.ParseBool(tkn.String())
+		if err != nil {
+			return key, &json.SemanticError{JSONKind: tkn.Kind(), GoType: rv.Type(), Err: err}
+		}
+
+		rv.SetBool(b)
+
+		return key, nil
 	}
+
+	err := json.UnmarshalDecode(dec, &key, dec.Options())
+	return key, err
 }
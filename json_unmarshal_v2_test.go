@@ -88,7 +88,6 @@ func TestUnmarshal_Errors(t *testing.T) {
 				data string
 				err  string
 			}{
-				{"string instead of object", `""`, `expected {, got string`},
 				{
 					"missing string for object name", `{"foo":{"foo":"foo","bar":1`,
 					`["foo"]: jsontext: unexpected EOF within "/foo" after offset 27`,
@@ -110,6 +109,74 @@ func TestUnmarshal_Errors(t *testing.T) {
 				})
 			}
 
+			t.Run("wrong kind", func(t *testing.T) {
+				for _, tc := range []struct {
+					data    string
+					kind    jsontext.Kind
+					offset  int64
+					pointer jsontext.Pointer
+				}{
+					{`""`, '"', 0, ""},
+					{` [1]`, '[', 1, ""},
+					{`{"m":{},"n":true}`, 't', 12, "/n"},
+				} {
+					v := reflect.New(reflect.StructOf([]reflect.StructField{
+						{Name: "M", Type: testType.tp, Tag: `json:"m"`},
+						{Name: "N", Type: testType.tp, Tag: `json:"n"`},
+					}))
+
+					target := v.Interface()
+					if tc.pointer == "" {
+						target = reflect.New(testType.tp).Interface()
+					}
+
+					err := json.Unmarshal([]byte(tc.data), target)
+
+					semErr := errAs[json.SemanticError](t, err)
+					if semErr.GoType != testType.tp {
+						t.Fatalf("got: %v, want: %v", semErr.GoType, testType.tp)
+					}
+
+					if semErr.JSONKind != tc.kind {
+						t.Fatalf("got: %v, want: %v", semErr.JSONKind, tc.kind)
+					}
+
+					if semErr.ByteOffset != tc.offset {
+						t.Fatalf("got: %v, want: %v", semErr.ByteOffset, tc.offset)
+					}
+
+					if semErr.JSONPointer != tc.pointer {
+						t.Fatalf("got: %v, want: %v", semErr.JSONPointer, tc.pointer)
+					}
+
+					if semErr.Err != nil {
+						t.Fatalf("got: %v, want: nil", semErr.Err)
+					}
+				}
+			})
+
+			t.Run("null", func(t *testing.T) {
+				v := reflect.New(reflect.StructOf([]reflect.StructField{
+					{Name: "M", Type: testType.tp, Tag: `json:"m"`},
+				}))
+
+				if err := json.Unmarshal([]byte(`{"m":{"foo":{}}}`), v.Interface()); err != nil {
+					t.Fatal(err)
+				}
+
+				if m := v.Elem().Field(0); m.IsNil() || m.Len() != 1 {
+					t.Fatalf("got: %v", m)
+				}
+
+				if err := json.Unmarshal([]byte(`{"m":null}`), v.Interface()); err != nil {
+					t.Fatal(err)
+				}
+
+				if m := v.Elem().Field(0); !m.IsNil() {
+					t.Fatalf("got: %v, want: nil", m)
+				}
+			})
+
 			t.Run("missing string for object name", func(t *testing.T) {
 				err := json.Unmarshal([]byte(`{"foo":1}`), reflect.New(testType.tp).Interface())
 
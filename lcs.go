@@ -0,0 +1,55 @@
+package ordmap
+
+import (
+	"cmp"
+	"slices"
+)
+
+// lcs returns the index pairs of a longest common subsequence of a and b in ascending order.
+// Since the keys of an ordered map are unique, it is a longest increasing subsequence
+// of the positions in a of the keys of b, which takes O(n log n) time and O(n) memory.
+func lcs[K comparable](a, b []K) [][2]int {
+	pos := make(map[K]int, len(a))
+	for i, k := range a {
+		pos[k] = i
+	}
+
+	// the index pairs of the keys that are in both, in the order of b
+	var pairs [][2]int
+	for j, k := range b {
+		if i, ok := pos[k]; ok {
+			pairs = append(pairs, [2]int{i, j})
+		}
+	}
+
+	// heads[l] is the pair that starts the increasing subsequence of length l+1 with the largest position in a,
+	// and next links each pair to the one after it in its subsequence.
+	// Building them from the end keeps the keys that come last in a in place when there is a choice.
+	var heads []int
+	next := make([]int, len(pairs))
+	for p := len(pairs) - 1; p >= 0; p-- {
+		i := pairs[p][0]
+
+		l, _ := slices.BinarySearchFunc(heads, i, func(h, i int) int { return cmp.Compare(i, pairs[h][0]) })
+		if l > 0 {
+			next[p] = heads[l-1]
+		}
+
+		if l == len(heads) {
+			heads = append(heads, p)
+		} else {
+			heads[l] = p
+		}
+	}
+
+	result := make([][2]int, len(heads))
+	if len(heads) == 0 {
+		return result
+	}
+
+	for l, p := 0, heads[len(heads)-1]; l < len(result); l, p = l+1, next[p] {
+		result[l] = pairs[p]
+	}
+
+	return result
+}
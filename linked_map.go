@@ -0,0 +1,338 @@
+package ordmap
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"iter"
+)
+
+var (
+	_ ByIndexer[string, any] = (*LinkedMap[string, any])(nil)
+	_ json.MarshalerTo       = (*LinkedMap[string, any])(nil)
+	_ json.UnmarshalerFrom   = (*LinkedMap[string, any])(nil)
+)
+
+// LinkedMap is an ordered map backed by a map and a doubly linked list.
+// Unlike OrderedMap, it does not need to look at all entries to set, delete or move a key,
+// and it iterates in order without sorting.
+// The zero value is an empty map ready to use.
+type LinkedMap[K comparable, V any] struct {
+	entries     map[K]*entry[K, V]
+	front, back *entry[K, V]
+}
+
+// entry is an element of the linked list of a LinkedMap.
+type entry[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next *entry[K, V]
+}
+
+// Len returns the number of entries in the map.
+func (lm *LinkedMap[K, V]) Len() int {
+	if lm == nil {
+		return 0
+	}
+
+	return len(lm.entries)
+}
+
+// Get returns the value for the key and whether the key is present in the map.
+func (lm *LinkedMap[K, V]) Get(key K) (V, bool) {
+	if e, ok := lm.entry(key); ok {
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Has reports whether the key is present in the map.
+func (lm *LinkedMap[K, V]) Has(key K) bool {
+	_, ok := lm.entry(key)
+	return ok
+}
+
+// Set sets a value in the map.
+// If the key already exists, it keeps its position. Otherwise, it is added at the end of the order.
+func (lm *LinkedMap[K, V]) Set(key K, v V) {
+	if e, ok := lm.entry(key); ok {
+		e.value = v
+		return
+	}
+
+	lm.pushBack(key, v)
+}
+
+// Delete removes the key from the map.
+func (lm *LinkedMap[K, V]) Delete(key K) {
+	if e, ok := lm.entry(key); ok {
+		lm.unlink(e)
+		delete(lm.entries, key)
+	}
+}
+
+// First returns the first key-value pair and whether the map has any entries.
+func (lm *LinkedMap[K, V]) First() (K, V, bool) {
+	if lm == nil {
+		return pair[K, V](nil)
+	}
+
+	return pair(lm.front)
+}
+
+// Last returns the last key-value pair and whether the map has any entries.
+func (lm *LinkedMap[K, V]) Last() (K, V, bool) {
+	if lm == nil {
+		return pair[K, V](nil)
+	}
+
+	return pair(lm.back)
+}
+
+// PopFront removes the first key-value pair and returns it.
+// It also reports whether the map had any entries.
+func (lm *LinkedMap[K, V]) PopFront() (K, V, bool) {
+	k, v, ok := lm.First()
+	if ok {
+		lm.Delete(k)
+	}
+
+	return k, v, ok
+}
+
+// PopBack removes the last key-value pair and returns it.
+// It also reports whether the map had any entries.
+func (lm *LinkedMap[K, V]) PopBack() (K, V, bool) {
+	k, v, ok := lm.Last()
+	if ok {
+		lm.Delete(k)
+	}
+
+	return k, v, ok
+}
+
+// MoveToFront moves the key to the front of the order.
+func (lm *LinkedMap[K, V]) MoveToFront(key K) error {
+	e, ok := lm.entry(key)
+	if !ok {
+		return errKeyNotFound(key)
+	}
+
+	if e != lm.front {
+		lm.unlink(e)
+		lm.linkBefore(e, lm.front)
+	}
+
+	return nil
+}
+
+// MoveToBack moves the key to the back of the order.
+func (lm *LinkedMap[K, V]) MoveToBack(key K) error {
+	e, ok := lm.entry(key)
+	if !ok {
+		return errKeyNotFound(key)
+	}
+
+	if e != lm.back {
+		lm.unlink(e)
+		lm.linkAfter(e, lm.back)
+	}
+
+	return nil
+}
+
+// MoveBefore moves the key so that it comes directly before the mark.
+func (lm *LinkedMap[K, V]) MoveBefore(key, mark K) error {
+	e, m, err := lm.entryAndMark(key, mark)
+	if err != nil || e == m {
+		return err
+	}
+
+	lm.unlink(e)
+	lm.linkBefore(e, m)
+
+	return nil
+}
+
+// MoveAfter moves the key so that it comes directly after the mark.
+func (lm *LinkedMap[K, V]) MoveAfter(key, mark K) error {
+	e, m, err := lm.entryAndMark(key, mark)
+	if err != nil || e == m {
+		return err
+	}
+
+	lm.unlink(e)
+	lm.linkAfter(e, m)
+
+	return nil
+}
+
+// Swap swaps the positions of two keys.
+func (lm *LinkedMap[K, V]) Swap(a, b K) error {
+	ea, eb, err := lm.entryAndMark(a, b)
+	if err != nil {
+		return err
+	}
+
+	ea.key, eb.key = eb.key, ea.key
+	ea.value, eb.value = eb.value, ea.value
+	lm.entries[ea.key], lm.entries[eb.key] = ea, eb
+
+	return nil
+}
+
+// ByIndex returns a sequence of key-value pairs in order.
+// It is safe to delete the current key while iterating.
+func (lm *LinkedMap[K, V]) ByIndex() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if lm == nil {
+			return
+		}
+
+		for e := lm.front; e != nil; {
+			next := e.next
+			if !yield(e.key, e.value) {
+				return
+			}
+
+			e = next
+		}
+	}
+}
+
+// MarshalJSONTo marshals the key-value pairs in order.
+// A map that was never set or was unmarshalled from JSON null is encoded as JSON null
+// if json.FormatNilMapAsNull is set.
+func (lm *LinkedMap[K, V]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if lm.entries == nil {
+		if asNull, _ := json.GetOption(enc.Options(), json.FormatNilMapAsNull); asNull {
+			return enc.WriteToken(jsontext.Null)
+		}
+	}
+
+	return MarshalJSONTo(lm, enc)
+}
+
+// UnmarshalJSONFrom unmarshals the key-value pairs in order.
+func (lm *LinkedMap[K, V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	if null, err := beginObject(dec); err != nil || null {
+		if null {
+			*lm = LinkedMap[K, V]{}
+		}
+
+		return err
+	}
+
+	*lm = LinkedMap[K, V]{entries: map[K]*entry[K, V]{}}
+
+	return decodeMembers(dec, func(key K) error {
+		var v V
+		if err := json.UnmarshalDecode(dec, &v, dec.Options()); err != nil {
+			return err
+		}
+
+		// like with OrderedMap, a duplicate key moves to the position of its last occurrence
+		lm.Delete(key)
+		lm.pushBack(key, v)
+
+		return nil
+	})
+}
+
+// entry returns the entry for the key and whether the key is present in the map.
+func (lm *LinkedMap[K, V]) entry(key K) (*entry[K, V], bool) {
+	if lm == nil {
+		return nil, false
+	}
+
+	e, ok := lm.entries[key]
+	return e, ok
+}
+
+// entryAndMark returns the entries of both keys or an error if one of them is missing.
+func (lm *LinkedMap[K, V]) entryAndMark(key, mark K) (*entry[K, V], *entry[K, V], error) {
+	e, ok := lm.entry(key)
+	if !ok {
+		return nil, nil, errKeyNotFound(key)
+	}
+
+	m, ok := lm.entry(mark)
+	if !ok {
+		return nil, nil, errKeyNotFound(mark)
+	}
+
+	return e, m, nil
+}
+
+// pushBack adds a new entry at the end of the order.
+func (lm *LinkedMap[K, V]) pushBack(key K, v V) {
+	// check if the map is nil and create it if it is
+	if lm.entries == nil {
+		lm.entries = map[K]*entry[K, V]{}
+	}
+
+	e := &entry[K, V]{key: key, value: v}
+	lm.entries[key] = e
+	lm.linkAfter(e, lm.back)
+}
+
+// linkBefore links an unlinked entry before the mark, which is nil if the list is empty.
+func (lm *LinkedMap[K, V]) linkBefore(e, mark *entry[K, V]) {
+	if mark == nil {
+		lm.front, lm.back = e, e
+		return
+	}
+
+	e.prev, e.next = mark.prev, mark
+	if mark.prev == nil {
+		lm.front = e
+	} else {
+		mark.prev.next = e
+	}
+
+	mark.prev = e
+}
+
+// linkAfter links an unlinked entry after the mark, which is nil if the list is empty.
+func (lm *LinkedMap[K, V]) linkAfter(e, mark *entry[K, V]) {
+	if mark == nil {
+		lm.front, lm.back = e, e
+		return
+	}
+
+	e.prev, e.next = mark, mark.next
+	if mark.next == nil {
+		lm.back = e
+	} else {
+		mark.next.prev = e
+	}
+
+	mark.next = e
+}
+
+// unlink takes the entry out of the linked list.
+func (lm *LinkedMap[K, V]) unlink(e *entry[K, V]) {
+	if e.prev == nil {
+		lm.front = e.next
+	} else {
+		e.prev.next = e.next
+	}
+
+	if e.next == nil {
+		lm.back = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+
+	e.prev, e.next = nil, nil
+}
+
+// pair returns the key and value of the entry and whether the entry exists.
+func pair[K comparable, V any](e *entry[K, V]) (key K, val V, ok bool) {
+	if e == nil {
+		return key, val, false
+	}
+
+	return e.key, e.value, true
+}
@@ -0,0 +1,210 @@
+package ordmap_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func TestLinkedMap(t *testing.T) {
+	t.Parallel()
+
+	var lm ordmap.LinkedMap[string, int]
+	if lm.Len() != 0 {
+		t.Fatalf("got: %d, want: 0", lm.Len())
+	}
+
+	if _, _, ok := lm.First(); ok {
+		t.Fatal("expected no entry")
+	}
+
+	if _, _, ok := lm.PopBack(); ok {
+		t.Fatal("expected no entry")
+	}
+
+	lm.Delete("foo") // no panic
+
+	lm.Set("foo", 1)
+	lm.Set("bar", 2)
+	lm.Set("baz", 3)
+	lm.Set("foo", 4)
+
+	if got, want := collectKeys(&lm), []string{"foo", "bar", "baz"}; !slices.Equal(got, want) {
+		t.Fatalf("got: %v, want: %v", got, want)
+	}
+
+	if v, ok := lm.Get("foo"); !ok || v != 4 {
+		t.Fatalf("got: %v, %t", v, ok)
+	}
+
+	if v, ok := lm.Get("qux"); ok || v != 0 {
+		t.Fatalf("got: %v, %t", v, ok)
+	}
+
+	if !lm.Has("bar") || lm.Has("qux") {
+		t.Fatal("unexpected membership")
+	}
+
+	if k, v, ok := lm.First(); !ok || k != "foo" || v != 4 {
+		t.Fatalf("got: %v, %v, %t", k, v, ok)
+	}
+
+	if k, v, ok := lm.Last(); !ok || k != "baz" || v != 3 {
+		t.Fatalf("got: %v, %v, %t", k, v, ok)
+	}
+
+	lm.Delete("bar")
+
+	if got, want := collectKeys(&lm), []string{"foo", "baz"}; !slices.Equal(got, want) {
+		t.Fatalf("got: %v, want: %v", got, want)
+	}
+
+	if k, v, ok := lm.PopFront(); !ok || k != "foo" || v != 4 {
+		t.Fatalf("got: %v, %v, %t", k, v, ok)
+	}
+
+	if k, v, ok := lm.PopBack(); !ok || k != "baz" || v != 3 {
+		t.Fatalf("got: %v, %v, %t", k, v, ok)
+	}
+
+	if lm.Len() != 0 {
+		t.Fatalf("got: %d, want: 0", lm.Len())
+	}
+
+	// a nil map behaves like an empty one
+	var nilMap *ordmap.LinkedMap[string, int]
+	if nilMap.Len() != 0 || nilMap.Has("foo") {
+		t.Fatal("expected an empty map")
+	}
+
+	if _, _, ok := nilMap.Last(); ok {
+		t.Fatal("expected no entry")
+	}
+
+	for range nilMap.ByIndex() {
+		t.Fatal("expected no entry")
+	}
+}
+
+func TestLinkedMap_Move(t *testing.T) {
+	t.Parallel()
+
+	var lm ordmap.LinkedMap[string, Value]
+	for _, k := range []string{"a", "b", "c", "d"} {
+		lm.Set(k, Value{Foo: k})
+	}
+
+	testMove(t, &lm, &lm)
+
+	for k, v := range lm.ByIndex() {
+		if v.Foo != k {
+			t.Fatalf("got: %v, want: %v", v.Foo, k)
+		}
+	}
+}
+
+func TestLinkedMap_ByIndex(t *testing.T) {
+	// NOTE: not parallel, as testing.AllocsPerRun panics in parallel tests
+
+	var lm ordmap.LinkedMap[string, int]
+	for i, k := range []string{"a", "b", "c", "d"} {
+		lm.Set(k, i)
+	}
+
+	for range lm.ByIndex() {
+		break // i.e. yield returns false
+	}
+
+	// deleting the current key while iterating
+	for k, v := range lm.ByIndex() {
+		if v%2 == 0 {
+			lm.Delete(k)
+		}
+	}
+
+	if got, want := collectKeys(&lm), []string{"b", "d"}; !slices.Equal(got, want) {
+		t.Fatalf("got: %v, want: %v", got, want)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() {
+		for range lm.ByIndex() {
+		}
+	}); allocs != 0 {
+		t.Fatalf("got: %v allocations, want: 0", allocs)
+	}
+}
+
+func TestLinkedMap_JSON(t *testing.T) {
+	t.Parallel()
+
+	const want = `{"foo":{"foo":"a","bar":6},"bar":{"foo":"b","bar":7},"baz":{"foo":"c","bar":8}}`
+
+	var lm ordmap.LinkedMap[string, *Value]
+	if err := json.Unmarshal([]byte(want), &lm); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(&lm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != want {
+		t.Fatalf("got: %v, want: %v", string(got), want)
+	}
+
+	// a duplicate key takes the position of its last occurrence
+	if err := json.Unmarshal([]byte(`{"a":{"bar":1},"b":{"bar":2},"a":{"bar":3}}`), &lm,
+		jsontext.AllowDuplicateNames(true)); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := collectKeys(&lm), []string{"b", "a"}; !slices.Equal(got, want) {
+		t.Fatalf("got: %v, want: %v", got, want)
+	}
+
+	if v, _ := lm.Get("a"); v.Bar != 3 {
+		t.Fatalf("got: %v, want: 3", v.Bar)
+	}
+
+	err = json.Unmarshal([]byte(`{"foo":1}`), &lm)
+
+	errKey := errAs[errpath.ErrKey](t, err)
+	if errKey.Key != "foo" {
+		t.Fatalf("got: %v, want: foo", errKey.Key)
+	}
+
+	err = json.Unmarshal([]byte(`[]`), &lm)
+	if semErr := errAs[json.SemanticError](t, err); semErr.JSONKind != '[' {
+		t.Fatalf("got: %v, want: [", semErr.JSONKind)
+	}
+
+	// null results in an empty map, which marshals as null if requested
+	if err := json.Unmarshal([]byte(`null`), &lm); err != nil {
+		t.Fatal(err)
+	} else if lm.Len() != 0 {
+		t.Fatalf("got: %d, want: 0", lm.Len())
+	}
+
+	for _, tc := range []struct {
+		data string
+		want string
+	}{
+		{`null`, `null`},
+		{`{}`, `{}`},
+	} {
+		if err := json.Unmarshal([]byte(tc.data), &lm); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, err := json.Marshal(&lm, json.FormatNilMapAsNull(true)); err != nil {
+			t.Fatal(err)
+		} else if string(got) != tc.want {
+			t.Fatalf("got: %v, want: %v", string(got), tc.want)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package ordmap
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/MarkRosemaker/errpath"
+)
+
+// Conflict describes how ours and theirs changed a key in different ways in a three-way merge.
+type Conflict[V any] struct {
+	Base, Ours, Theirs V
+	// InBase, InOurs and InTheirs report whether the key is present in the respective map.
+	InBase, InOurs, InTheirs bool
+	// Order reports that ours and theirs moved the key to different positions.
+	Order bool
+}
+
+// Error fulfills the error interface.
+func (c *Conflict[_]) Error() string {
+	switch {
+	case c.Order:
+		return "conflicting changes: moved to different positions"
+	case !c.InOurs:
+		return "conflicting changes: deleted in ours, changed in theirs"
+	case !c.InTheirs:
+		return "conflicting changes: changed in ours, deleted in theirs"
+	case !c.InBase:
+		return "conflicting changes: added with different values"
+	default:
+		return "conflicting changes: changed to different values"
+	}
+}
+
+// Unwrap returns ErrConflict.
+func (c *Conflict[_]) Unwrap() error { return ErrConflict }
+
+// ThreeWayMerge merges the changes that ours and theirs made to base into a new ordered map.
+//
+// A key that only one side added, deleted or changed gets that change, and a key that both sides
+// changed in the same way gets that change, too. The order is merged like lines of text by diff3,
+// so independent reorderings and additions merge cleanly.
+// If equal is nil, values are compared with reflect.DeepEqual.
+//
+// Keys that both sides changed in different ways keep the version of ours and are reported
+// as a *Conflict wrapped in an errpath.ErrKey, joined in the order of the result.
+func ThreeWayMerge[K comparable, V any](
+	base, ours, theirs ByIndexer[K, V],
+	equal func(V, V) bool,
+) (OrderedMap[K, V], error) {
+	if equal == nil {
+		equal = func(x, y V) bool { return reflect.DeepEqual(x, y) }
+	}
+
+	baseKeys, baseVals := collectEntries(base)
+	ourKeys, ourVals := collectEntries(ours)
+	theirKeys, theirVals := collectEntries(theirs)
+
+	baseMap, ourMap, theirMap := zipMap(baseKeys, baseVals), zipMap(ourKeys, ourVals), zipMap(theirKeys, theirVals)
+
+	conflicts := map[K]*Conflict[V]{}
+	conflict := func(k K) *Conflict[V] {
+		c, ok := conflicts[k]
+		if !ok {
+			c = &Conflict[V]{}
+			c.Base, c.InBase = baseMap.get(k)
+			c.Ours, c.InOurs = ourMap.get(k)
+			c.Theirs, c.InTheirs = theirMap.get(k)
+			conflicts[k] = c
+		}
+
+		return c
+	}
+
+	// merge the values of all keys
+	merged := map[K]V{}
+	for _, k := range slices.Concat(baseKeys, ourKeys, theirKeys) {
+		if _, done := merged[k]; done {
+			continue
+		}
+
+		if v, keep, ok := mergeValue(k, baseMap, ourMap, theirMap, equal); !ok {
+			if c := conflict(k); c.InOurs {
+				merged[k] = c.Ours
+			}
+		} else if keep {
+			merged[k] = v
+		}
+	}
+
+	// merge the order and report keys that both sides moved differently
+	var order []K
+	seen := map[K]bool{}
+	for _, k := range mergeSequence(baseKeys, ourKeys, theirKeys, func(k K) { conflict(k).Order = true }) {
+		if seen[k] {
+			if _, inBase := baseMap[k]; inBase {
+				conflict(k).Order = true
+			}
+
+			continue
+		}
+
+		seen[k] = true
+		order = append(order, k)
+	}
+
+	result := OrderedMap[K, V]{}
+	var errs []error
+
+	addKey := func(k K) {
+		v, ok := merged[k]
+		if !ok || result.Has(k) {
+			return
+		}
+
+		result.Set(k, v)
+
+		if c, ok := conflicts[k]; ok {
+			errs = append(errs, &errpath.ErrKey{Key: fmt.Sprint(k), Err: c})
+		}
+	}
+
+	for _, k := range order {
+		addKey(k)
+	}
+
+	// keys that the order does not contain, e.g. after a conflict, come last in the order of ours
+	for _, k := range slices.Concat(ourKeys, theirKeys) {
+		addKey(k)
+	}
+
+	// report conflicts of keys that are not in the result, e.g. deleted in ours
+	for _, k := range slices.Concat(baseKeys, theirKeys) {
+		if c, ok := conflicts[k]; ok && !result.Has(k) {
+			errs = append(errs, &errpath.ErrKey{Key: fmt.Sprint(k), Err: c})
+			delete(conflicts, k)
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// entries maps keys to values.
+type entries[K comparable, V any] map[K]V
+
+func (e entries[K, V]) get(k K) (V, bool) {
+	v, ok := e[k]
+	return v, ok
+}
+
+// zipMap returns a map of the keys to the values.
+func zipMap[K comparable, V any](keys []K, vals []V) entries[K, V] {
+	m := make(entries[K, V], len(keys))
+	for i, k := range keys {
+		m[k] = vals[i]
+	}
+
+	return m
+}
+
+// mergeValue merges the value of a key. It reports whether the key is kept
+// and whether the changes could be merged.
+func mergeValue[K comparable, V any](
+	k K, base, ours, theirs entries[K, V],
+	equal func(V, V) bool,
+) (v V, keep bool, ok bool) {
+	bv, inBase := base[k]
+	ov, inOurs := ours[k]
+	tv, inTheirs := theirs[k]
+
+	switch {
+	case inOurs && inTheirs:
+		switch {
+		case equal(ov, tv):
+			return ov, true, true
+		case inBase && equal(bv, ov):
+			return tv, true, true
+		case inBase && equal(bv, tv):
+			return ov, true, true
+		default:
+			return v, false, false
+		}
+	case inOurs: // deleted or not added in theirs
+		if !inBase {
+			return ov, true, true
+		}
+
+		return v, false, equal(bv, ov)
+	case inTheirs: // deleted or not added in ours
+		if !inBase {
+			return tv, true, true
+		}
+
+		return v, false, equal(bv, tv)
+	default: // deleted in both
+		return v, false, true
+	}
+}
+
+// mergeSequence merges the changes that ours and theirs made to the order of base like diff3.
+// Where both changed the same part of the order, it combines the changes
+// and calls conflict for each key that both sides ordered differently.
+func mergeSequence[K comparable](base, ours, theirs []K, conflict func(K)) []K {
+	// map the positions in base to the matching positions in ours and theirs
+	inOurs, inTheirs := map[int]int{}, map[int]int{}
+	for _, pair := range lcs(base, ours) {
+		inOurs[pair[0]] = pair[1]
+	}
+
+	for _, pair := range lcs(base, theirs) {
+		inTheirs[pair[0]] = pair[1]
+	}
+
+	// a key that both sides moved must end up after the same key
+	ourSet, theirSet := setOf(ours), setOf(theirs)
+	inAll := func(k K) bool { return ourSet[k] && theirSet[k] }
+	ourPrev, theirPrev := predecessors(ours, inAll), predecessors(theirs, inAll)
+
+	for i, k := range base {
+		_, okO := inOurs[i]
+		_, okT := inTheirs[i]
+
+		if !okO && !okT && inAll(k) && ourPrev[k] != theirPrev[k] {
+			conflict(k)
+		}
+	}
+
+	var result []K
+
+	b, o, t := 0, 0, 0
+	for {
+		// find the next key that is stable in all three
+		next := b
+		for ; next < len(base); next++ {
+			_, okO := inOurs[next]
+			_, okT := inTheirs[next]
+
+			if okO && okT {
+				break
+			}
+		}
+
+		if next == len(base) {
+			return append(result, mergeChunk(base[b:], ours[o:], theirs[t:], ourSet, theirSet, conflict)...)
+		}
+
+		nextO, nextT := inOurs[next], inTheirs[next]
+		result = append(result, mergeChunk(base[b:next], ours[o:nextO], theirs[t:nextT], ourSet, theirSet, conflict)...)
+		result = append(result, base[next])
+		b, o, t = next+1, nextO+1, nextT+1
+	}
+}
+
+// setOf returns the set of the keys.
+func setOf[K comparable](keys []K) map[K]bool {
+	set := make(map[K]bool, len(keys))
+	for _, k := range keys {
+		set[k] = true
+	}
+
+	return set
+}
+
+// predecessors maps each key that satisfies keep to the index of the previous such key, or -1.
+func predecessors[K comparable](keys []K, keep func(K) bool) map[K]int {
+	prev, last := map[K]int{}, -1
+	for i, k := range keys {
+		if keep(k) {
+			prev[k] = last
+			last = i
+		}
+	}
+
+	return prev
+}
+
+// mergeChunk merges a part of the order that lies between two keys that are stable in all three.
+// The sets contain all keys of ours and theirs.
+func mergeChunk[K comparable](base, ours, theirs []K, ourSet, theirSet map[K]bool, conflict func(K)) []K {
+	switch {
+	case slices.Equal(base, ours):
+		return theirs
+	case slices.Equal(base, theirs), slices.Equal(ours, theirs):
+		return ours
+	}
+
+	// both changed this part, so compare the order of the keys that are in all three
+	inBase, inOurs, inTheirs := setOf(base), setOf(ours), setOf(theirs)
+	inAll := func(k K) bool { return inBase[k] && inOurs[k] && inTheirs[k] }
+
+	b := slices.DeleteFunc(slices.Clone(base), func(k K) bool { return !inAll(k) })
+	o := slices.DeleteFunc(slices.Clone(ours), func(k K) bool { return !inAll(k) })
+	t := slices.DeleteFunc(slices.Clone(theirs), func(k K) bool { return !inAll(k) })
+
+	// follow the side that reordered them, preferring ours, without the keys the other side moved away,
+	// and add the keys that only the other side added or moved here
+	primary, other, inPrimary, inOther, otherSet := ours, theirs, inOurs, inTheirs, theirSet
+	if slices.Equal(b, o) && !slices.Equal(b, t) {
+		primary, other, inPrimary, inOther, otherSet = theirs, ours, inTheirs, inOurs, ourSet
+	}
+
+	result := slices.DeleteFunc(slices.Clone(primary), func(k K) bool {
+		return inBase[k] && !inOther[k] && otherSet[k]
+	})
+
+	for _, k := range other {
+		if !inPrimary[k] && !inBase[k] {
+			result = append(result, k)
+		}
+	}
+
+	if slices.Equal(o, t) || slices.Equal(b, o) || slices.Equal(b, t) {
+		return result
+	}
+
+	// both reordered them differently, report the keys that ours moved against theirs
+	stay := map[K]bool{}
+	for _, pair := range lcs(o, t) {
+		stay[o[pair[0]]] = true
+	}
+
+	for _, k := range o {
+		if !stay[k] {
+			conflict(k)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,212 @@
+package ordmap_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func TestThreeWayMerge(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name               string
+		base, ours, theirs ordmap.OrderedMap[string, int]
+		want               ordmap.OrderedMap[string, int]
+	}{
+		{
+			name:   "independent changes",
+			base:   newMap("a", 1, "b", 2, "c", 3),
+			ours:   newMap("a", 10, "b", 2, "c", 3, "d", 4),
+			theirs: newMap("a", 1, "e", 5, "c", 3),
+			want:   newMap("a", 10, "e", 5, "c", 3, "d", 4),
+		},
+		{
+			name:   "same changes",
+			base:   newMap("a", 1, "b", 2),
+			ours:   newMap("b", 20, "a", 1, "c", 3),
+			theirs: newMap("b", 20, "a", 1, "c", 3),
+			want:   newMap("b", 20, "a", 1, "c", 3),
+		},
+		{
+			name:   "independent reorderings",
+			base:   newMap("a", 1, "b", 2, "c", 3, "d", 4, "e", 5),
+			ours:   newMap("b", 2, "c", 3, "d", 4, "e", 5, "a", 1),
+			theirs: newMap("e", 5, "a", 1, "b", 2, "c", 3, "d", 4),
+			want:   newMap("e", 5, "b", 2, "c", 3, "d", 4, "a", 1),
+		},
+		{
+			name:   "additions at the same place",
+			base:   newMap("a", 1),
+			ours:   newMap("a", 1, "x", 1),
+			theirs: newMap("a", 1, "y", 2),
+			want:   newMap("a", 1, "x", 1, "y", 2),
+		},
+		{
+			name:   "reorder and change",
+			base:   newMap("a", 1, "b", 2, "c", 3),
+			ours:   newMap("c", 3, "a", 1, "b", 2),
+			theirs: newMap("a", 1, "b", 20, "c", 30),
+			want:   newMap("c", 30, "a", 1, "b", 20),
+		},
+		{
+			name:   "reorder in theirs and delete in ours",
+			base:   newMap("a", 1, "b", 2, "c", 3),
+			ours:   newMap("a", 1, "c", 3),
+			theirs: newMap("c", 3, "b", 2, "a", 1),
+			want:   newMap("c", 3, "a", 1),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ordmap.ThreeWayMerge(tc.base, tc.ours, tc.theirs, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertSameMap(t, got, tc.want)
+		})
+	}
+
+	t.Run("one side", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(3, 4))
+
+		for range 200 {
+			base, changed := randomMap(r), randomMap(r)
+
+			got, err := ordmap.ThreeWayMerge(base, changed, base, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertSameMap(t, got, changed)
+
+			if got, err = ordmap.ThreeWayMerge(base, base, changed, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			assertSameMap(t, got, changed)
+		}
+	})
+
+	t.Run("large", func(t *testing.T) {
+		base, ours := largeMaps()
+
+		theirs := ordmap.OrderedMap[string, int]{}
+		for k, v := range base.ByIndex() {
+			theirs.Set(k, v)
+		}
+
+		theirs.Set("4999", -1)
+
+		got, err := ordmap.ThreeWayMerge(base, ours, theirs, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ours.Set("4999", -1)
+		assertSameMap(t, got, ours)
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		base := newMap("a", 1, "b", 2, "c", 3, "d", 4)
+		ours := newMap("a", 10, "c", 30, "d", 4, "x", 1)
+		theirs := newMap("a", 11, "b", 20, "d", 4, "x", 2)
+
+		got, err := ordmap.ThreeWayMerge(base, ours, theirs, nil)
+		if !errors.Is(err, ordmap.ErrConflict) {
+			t.Fatalf("got: %v", err)
+		}
+
+		// the result keeps the version of ours
+		assertSameMap(t, got, ours)
+
+		errs := err.(interface{ Unwrap() []error }).Unwrap()
+
+		var keys []string
+		for _, err := range errs {
+			keys = append(keys, errAs[errpath.ErrKey](t, err).Key)
+		}
+
+		if want := []string{"a", "c", "x", "b"}; !slices.Equal(keys, want) {
+			t.Fatalf("got: %v, want: %v", keys, want)
+		}
+
+		c := errAs[ordmap.Conflict[int]](t, errs[0])
+		if c.Base != 1 || c.Ours != 10 || c.Theirs != 11 || !c.InBase || !c.InOurs || !c.InTheirs || c.Order {
+			t.Fatalf("got: %+v", c)
+		}
+
+		for i, want := range []string{
+			`["a"]: conflicting changes: changed to different values`,
+			`["c"]: conflicting changes: changed in ours, deleted in theirs`,
+			`["x"]: conflicting changes: added with different values`,
+			`["b"]: conflicting changes: deleted in ours, changed in theirs`,
+		} {
+			if errs[i].Error() != want {
+				t.Fatalf("got: %v, want: %v", errs[i], want)
+			}
+		}
+	})
+
+	t.Run("order conflict", func(t *testing.T) {
+		base := newMap("a", 1, "b", 2, "c", 3, "d", 4, "e", 5)
+		ours := newMap("b", 2, "c", 3, "d", 4, "e", 5, "a", 1)
+		theirs := newMap("b", 2, "c", 3, "a", 1, "d", 4, "e", 5)
+
+		got, err := ordmap.ThreeWayMerge(base, ours, theirs, nil)
+		if !errors.Is(err, ordmap.ErrConflict) {
+			t.Fatalf("got: %v", err)
+		}
+
+		if c := errAs[ordmap.Conflict[int]](t, err); !c.Order {
+			t.Fatalf("got: %+v", c)
+		}
+
+		if got.Len() != 5 {
+			t.Fatalf("got: %v", collectKeys(got))
+		}
+
+		if err := got.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func randomMap(r *rand.Rand) ordmap.OrderedMap[string, int] {
+	keys := make([]string, 6)
+	for i := range keys {
+		keys[i] = fmt.Sprint(i)
+	}
+
+	r.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+
+	om := ordmap.OrderedMap[string, int]{}
+	for _, k := range keys[:r.IntN(len(keys)+1)] {
+		om.Set(k, r.IntN(2))
+	}
+
+	return om
+}
+
+func assertSameMap(t *testing.T, got, want ordmap.OrderedMap[string, int]) {
+	t.Helper()
+
+	if !slices.Equal(collectKeys(got), collectKeys(want)) {
+		t.Fatalf("got: %v, want: %v", collectKeys(got), collectKeys(want))
+	}
+
+	for k, v := range want.ByIndex() {
+		if gv, _ := got.Get(k); gv != v {
+			t.Fatalf("%s: got: %d, want: %d", k, gv, v)
+		}
+	}
+
+	if err := got.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,136 @@
+package ordmap
+
+import "slices"
+
+// MoveToFront moves the key to the front of the order.
+func (om OrderedMap[K, V]) MoveToFront(key K) error {
+	return MoveToFront(om, key, getIndex, setIndex)
+}
+
+// MoveToBack moves the key to the back of the order.
+func (om OrderedMap[K, V]) MoveToBack(key K) error {
+	return MoveToBack(om, key, getIndex, setIndex)
+}
+
+// MoveBefore moves the key so that it comes directly before the mark.
+func (om OrderedMap[K, V]) MoveBefore(key, mark K) error {
+	return MoveBefore(om, key, mark, getIndex, setIndex)
+}
+
+// MoveAfter moves the key so that it comes directly after the mark.
+func (om OrderedMap[K, V]) MoveAfter(key, mark K) error {
+	return MoveAfter(om, key, mark, getIndex, setIndex)
+}
+
+// Swap swaps the positions of two keys.
+func (om OrderedMap[K, V]) Swap(a, b K) error {
+	return Swap(om, a, b, getIndex, setIndex)
+}
+
+// MoveToFront is a helper function to move a key to the front of the order and set the indices accordingly.
+func MoveToFront[M ~map[K]V, K comparable, V any](
+	m M, key K,
+	getIndex func(V) int,
+	setIndex func(V, int) V,
+) error {
+	return move(m, key, getIndex, setIndex, func([]K, int) (int, error) { return 0, nil })
+}
+
+// MoveToBack is a helper function to move a key to the back of the order and set the indices accordingly.
+func MoveToBack[M ~map[K]V, K comparable, V any](
+	m M, key K,
+	getIndex func(V) int,
+	setIndex func(V, int) V,
+) error {
+	return move(m, key, getIndex, setIndex, func(keys []K, _ int) (int, error) { return len(keys), nil })
+}
+
+// MoveBefore is a helper function to move a key directly before the mark and set the indices accordingly.
+func MoveBefore[M ~map[K]V, K comparable, V any](
+	m M, key, mark K,
+	getIndex func(V) int,
+	setIndex func(V, int) V,
+) error {
+	return move(m, key, getIndex, setIndex, func(keys []K, pos int) (int, error) {
+		if mark == key {
+			return pos, nil
+		}
+
+		return position(keys, mark)
+	})
+}
+
+// MoveAfter is a helper function to move a key directly after the mark and set the indices accordingly.
+func MoveAfter[M ~map[K]V, K comparable, V any](
+	m M, key, mark K,
+	getIndex func(V) int,
+	setIndex func(V, int) V,
+) error {
+	return move(m, key, getIndex, setIndex, func(keys []K, pos int) (int, error) {
+		if mark == key {
+			return pos, nil
+		}
+
+		pos, err := position(keys, mark)
+		return pos + 1, err
+	})
+}
+
+// Swap is a helper function to swap the positions of two keys and set the indices accordingly.
+func Swap[M ~map[K]V, K comparable, V any](
+	m M, a, b K,
+	getIndex func(V) int,
+	setIndex func(V, int) V,
+) error {
+	keys := orderedKeys(m, getIndex)
+
+	i, err := position(keys, a)
+	if err != nil {
+		return err
+	}
+
+	j, err := position(keys, b)
+	if err != nil {
+		return err
+	}
+
+	keys[i], keys[j] = keys[j], keys[i]
+	setIndices(m, keys, setIndex)
+
+	return nil
+}
+
+// move takes the key out of the order and inserts it at the position
+// that target returns for the remaining keys and the previous position of the key.
+func move[M ~map[K]V, K comparable, V any](
+	m M, key K,
+	getIndex func(V) int,
+	setIndex func(V, int) V,
+	target func([]K, int) (int, error),
+) error {
+	keys := orderedKeys(m, getIndex)
+
+	pos, err := position(keys, key)
+	if err != nil {
+		return err
+	}
+
+	keys = slices.Delete(keys, pos, pos+1)
+
+	if pos, err = target(keys, pos); err != nil {
+		return err
+	}
+
+	setIndices(m, slices.Insert(keys, pos, key), setIndex)
+
+	return nil
+}
+
+// position returns the position of the key in the given keys.
+func position[K comparable](keys []K, key K) (int, error) {
+	if pos := slices.Index(keys, key); pos != -1 {
+		return pos, nil
+	}
+
+	return 0, errKeyNotFound(key)
+}
@@ -0,0 +1,130 @@
+package ordmap_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func (om UserDefinedOrderedMap) MoveToFront(key string) error {
+	return ordmap.MoveToFront(om, key, getIndex, setIndex)
+}
+
+func (om UserDefinedOrderedMap) MoveToBack(key string) error {
+	return ordmap.MoveToBack(om, key, getIndex, setIndex)
+}
+
+func (om UserDefinedOrderedMap) MoveBefore(key, mark string) error {
+	return ordmap.MoveBefore(om, key, mark, getIndex, setIndex)
+}
+
+func (om UserDefinedOrderedMap) MoveAfter(key, mark string) error {
+	return ordmap.MoveAfter(om, key, mark, getIndex, setIndex)
+}
+
+func (om UserDefinedOrderedMap) Swap(a, b string) error {
+	return ordmap.Swap(om, a, b, getIndex, setIndex)
+}
+
+type mover interface {
+	MoveToFront(key string) error
+	MoveToBack(key string) error
+	MoveBefore(key, mark string) error
+	MoveAfter(key, mark string) error
+	Swap(a, b string) error
+}
+
+func TestMove(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		var om UserDefinedOrderedMap
+		for _, k := range []string{"a", "b", "c", "d"} {
+			om.Set(k, &ValueWithIndex{Foo: k})
+		}
+
+		testMove[*ValueWithIndex](t, om, om)
+	})
+
+	t.Run("ordered map", func(t *testing.T) {
+		var om OrderedMap
+		for _, k := range []string{"a", "b", "c", "d"} {
+			om.Set(k, Value{Foo: k})
+		}
+
+		testMove[Value](t, om, om)
+	})
+}
+
+func testMove[V any](t *testing.T, om ordmap.ByIndexer[string, V], m mover) {
+	t.Helper()
+
+	for _, tc := range []struct {
+		name string
+		move func() error
+		want []string
+	}{
+		{"move to front", func() error { return m.MoveToFront("c") }, []string{"c", "a", "b", "d"}},
+		{"move to front again", func() error { return m.MoveToFront("c") }, []string{"c", "a", "b", "d"}},
+		{"move to back", func() error { return m.MoveToBack("c") }, []string{"a", "b", "d", "c"}},
+		{"move before", func() error { return m.MoveBefore("c", "b") }, []string{"a", "c", "b", "d"}},
+		{"move before first", func() error { return m.MoveBefore("d", "a") }, []string{"d", "a", "c", "b"}},
+		{"move before itself", func() error { return m.MoveBefore("a", "a") }, []string{"d", "a", "c", "b"}},
+		{"move after", func() error { return m.MoveAfter("d", "c") }, []string{"a", "c", "d", "b"}},
+		{"move after last", func() error { return m.MoveAfter("a", "b") }, []string{"c", "d", "b", "a"}},
+		{"move after itself", func() error { return m.MoveAfter("d", "d") }, []string{"c", "d", "b", "a"}},
+		{"swap", func() error { return m.Swap("c", "a") }, []string{"a", "d", "b", "c"}},
+		{"swap with itself", func() error { return m.Swap("b", "b") }, []string{"a", "d", "b", "c"}},
+	} {
+		if err := tc.move(); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		if got := collectKeys(om); !slices.Equal(got, tc.want) {
+			t.Fatalf("%s: got: %v, want: %v", tc.name, got, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		name string
+		move func() error
+		key  string
+	}{
+		{"move missing to front", func() error { return m.MoveToFront("x") }, "x"},
+		{"move missing to back", func() error { return m.MoveToBack("x") }, "x"},
+		{"move missing before", func() error { return m.MoveBefore("x", "a") }, "x"},
+		{"move before missing", func() error { return m.MoveBefore("a", "y") }, "y"},
+		{"move missing after", func() error { return m.MoveAfter("x", "a") }, "x"},
+		{"move after missing", func() error { return m.MoveAfter("a", "y") }, "y"},
+		{"swap missing", func() error { return m.Swap("x", "a") }, "x"},
+		{"swap with missing", func() error { return m.Swap("a", "y") }, "y"},
+	} {
+		err := tc.move()
+
+		errKey := errAs[errpath.ErrKey](t, err)
+		if errKey.Key != tc.key {
+			t.Fatalf("%s: got: %v, want: %v", tc.name, errKey.Key, tc.key)
+		}
+
+		if !errors.Is(err, ordmap.ErrKeyNotFound) {
+			t.Fatalf("%s: got: %v, want: %v", tc.name, err, ordmap.ErrKeyNotFound)
+		}
+
+		// the order is unchanged
+		if got, want := collectKeys(om), []string{"a", "d", "b", "c"}; !slices.Equal(got, want) {
+			t.Fatalf("%s: got: %v, want: %v", tc.name, got, want)
+		}
+	}
+}
+
+func collectKeys[V any](om ordmap.ByIndexer[string, V]) []string {
+	keys := []string{}
+	for k := range om.ByIndex() {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
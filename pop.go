@@ -0,0 +1,35 @@
+package ordmap
+
+// PopFront removes the first key-value pair by index and returns it.
+// It also reports whether the map had any entries.
+func (om OrderedMap[K, V]) PopFront() (K, V, bool) {
+	k, v, ok := PopFront(om, getIndex)
+	return k, v.V, ok
+}
+
+// PopBack removes the last key-value pair by index and returns it.
+// It also reports whether the map had any entries.
+func (om OrderedMap[K, V]) PopBack() (K, V, bool) {
+	k, v, ok := PopBack(om, getIndex)
+	return k, v.V, ok
+}
+
+// PopFront is a helper function to remove and return the first key-value pair of a map by index.
+func PopFront[M ~map[K]V, K comparable, V any](m M, getIndex func(V) int) (K, V, bool) {
+	k, v, ok := First(m, getIndex)
+	if ok {
+		delete(m, k)
+	}
+
+	return k, v, ok
+}
+
+// PopBack is a helper function to remove and return the last key-value pair of a map by index.
+func PopBack[M ~map[K]V, K comparable, V any](m M, getIndex func(V) int) (K, V, bool) {
+	k, v, ok := Last(m, getIndex)
+	if ok {
+		delete(m, k)
+	}
+
+	return k, v, ok
+}
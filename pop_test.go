@@ -0,0 +1,81 @@
+package ordmap_test
+
+import (
+	"testing"
+
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func (om UserDefinedOrderedMap) PopFront() (string, *ValueWithIndex, bool) {
+	return ordmap.PopFront(om, getIndex)
+}
+
+func (om UserDefinedOrderedMap) PopBack() (string, *ValueWithIndex, bool) {
+	return ordmap.PopBack(om, getIndex)
+}
+
+func TestPop(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		var om UserDefinedOrderedMap
+		if _, _, ok := om.PopFront(); ok {
+			t.Fatal("expected no entry")
+		}
+
+		if _, _, ok := om.PopBack(); ok {
+			t.Fatal("expected no entry")
+		}
+
+		om.Set("foo", &ValueWithIndex{Foo: "a"})
+		om.Set("bar", &ValueWithIndex{Foo: "b"})
+		om.Set("baz", &ValueWithIndex{Foo: "c"})
+
+		if k, v, ok := om.PopFront(); !ok || k != "foo" || v.Foo != "a" {
+			t.Fatalf("got: %v, %v, %t", k, v, ok)
+		}
+
+		if k, v, ok := om.PopBack(); !ok || k != "baz" || v.Foo != "c" {
+			t.Fatalf("got: %v, %v, %t", k, v, ok)
+		}
+
+		if len(om) != 1 {
+			t.Fatalf("got: %d, want: 1", len(om))
+		}
+
+		if _, ok := om["bar"]; !ok {
+			t.Fatal("expected bar to be left")
+		}
+	})
+
+	t.Run("ordered map", func(t *testing.T) {
+		var om OrderedMap
+		if _, _, ok := om.PopFront(); ok {
+			t.Fatal("expected no entry")
+		}
+
+		if _, _, ok := om.PopBack(); ok {
+			t.Fatal("expected no entry")
+		}
+
+		om.Set("foo", Value{Foo: "a"})
+		om.Set("bar", Value{Foo: "b"})
+		om.Set("baz", Value{Foo: "c"})
+
+		if k, v, ok := om.PopBack(); !ok || k != "baz" || v.Foo != "c" {
+			t.Fatalf("got: %v, %v, %t", k, v, ok)
+		}
+
+		if k, v, ok := om.PopBack(); !ok || k != "bar" || v.Foo != "b" {
+			t.Fatalf("got: %v, %v, %t", k, v, ok)
+		}
+
+		if k, v, ok := om.PopFront(); !ok || k != "foo" || v.Foo != "a" {
+			t.Fatalf("got: %v, %v, %t", k, v, ok)
+		}
+
+		if om.Len() != 0 {
+			t.Fatalf("got: %d, want: 0", om.Len())
+		}
+	})
+}
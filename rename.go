@@ -0,0 +1,45 @@
+package ordmap
+
+import (
+	"fmt"
+
+	"github.com/MarkRosemaker/errpath"
+)
+
+// Rename changes the key of an entry while keeping its value and position.
+// It fails if the new key already exists.
+func (om OrderedMap[K, V]) Rename(oldKey, newKey K) error {
+	return Rename(om, oldKey, newKey)
+}
+
+// RenameOverwrite changes the key of an entry while keeping its value and position.
+// If the new key already exists, its entry is replaced.
+func (om OrderedMap[K, V]) RenameOverwrite(oldKey, newKey K) error {
+	return RenameOverwrite(om, oldKey, newKey)
+}
+
+// Rename is a helper function to change the key of an entry while keeping its value and index.
+// It fails if the new key already exists.
+func Rename[M ~map[K]V, K comparable, V any](m M, oldKey, newKey K) error {
+	if _, ok := m[oldKey]; ok && oldKey != newKey {
+		if _, exists := m[newKey]; exists {
+			return &errpath.ErrKey{Key: fmt.Sprint(newKey), Err: ErrKeyExists}
+		}
+	}
+
+	return RenameOverwrite(m, oldKey, newKey)
+}
+
+// RenameOverwrite is a helper function to change the key of an entry while keeping its value and index.
+// If the new key already exists, its entry is replaced.
+func RenameOverwrite[M ~map[K]V, K comparable, V any](m M, oldKey, newKey K) error {
+	v, ok := m[oldKey]
+	if !ok {
+		return errKeyNotFound(oldKey)
+	}
+
+	delete(m, oldKey)
+	m[newKey] = v
+
+	return nil
+}
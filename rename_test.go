@@ -0,0 +1,88 @@
+package ordmap_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func TestRename(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		var om UserDefinedOrderedMap
+		om.Set("a", &ValueWithIndex{Foo: "a"})
+		om.Set("b", &ValueWithIndex{Foo: "b"})
+		om.Set("c", &ValueWithIndex{Foo: "c"})
+
+		if err := ordmap.Rename(om, "b", "x"); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := collectKeys(om), []string{"a", "x", "c"}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+
+		if om["x"].Foo != "b" {
+			t.Fatalf("got: %v, want: b", om["x"].Foo)
+		}
+	})
+
+	t.Run("ordered map", func(t *testing.T) {
+		var om OrderedMap
+		om.Set("a", Value{Foo: "a"})
+		om.Set("b", Value{Foo: "b"})
+		om.Set("c", Value{Foo: "c"})
+
+		if err := om.Rename("a", "x"); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := collectKeys(om), []string{"x", "b", "c"}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+
+		// renaming to itself does nothing
+		if err := om.Rename("b", "b"); err != nil {
+			t.Fatal(err)
+		}
+
+		errKey := errAs[errpath.ErrKey](t, om.Rename("x", "c"))
+		if errKey.Key != "c" {
+			t.Fatalf("got: %v, want: c", errKey.Key)
+		} else if !errors.Is(errKey, ordmap.ErrKeyExists) {
+			t.Fatalf("got: %v, want: %v", errKey, ordmap.ErrKeyExists)
+		}
+
+		errKey = errAs[errpath.ErrKey](t, om.Rename("y", "z"))
+		if errKey.Key != "y" {
+			t.Fatalf("got: %v, want: y", errKey.Key)
+		} else if !errors.Is(errKey, ordmap.ErrKeyNotFound) {
+			t.Fatalf("got: %v, want: %v", errKey, ordmap.ErrKeyNotFound)
+		}
+
+		if got, want := collectKeys(om), []string{"x", "b", "c"}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+
+		// overwriting replaces the existing entry
+		if err := om.RenameOverwrite("c", "x"); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := collectKeys(om), []string{"b", "x"}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+
+		if v, _ := om.Get("x"); v.Foo != "c" {
+			t.Fatalf("got: %v, want: c", v.Foo)
+		}
+
+		if err := om.RenameOverwrite("y", "z"); !errors.Is(err, ordmap.ErrKeyNotFound) {
+			t.Fatalf("got: %v, want: %v", err, ordmap.ErrKeyNotFound)
+		}
+	})
+}
@@ -1,11 +1,13 @@
 package ordmap
 
-// Set sets a value in the map, adding it at the end of the order.
+// Set sets a value in the map.
+// If the key already exists, it keeps its position. Otherwise, it is added at the end of the order.
 func (om *OrderedMap[K, V]) Set(key K, v V) {
 	Set(om, key, Value[V]{V: v}, getIndex[V], setIndex[V])
 }
 
-// Set is a helper function to set a value in the map, adding it at the end of the order.
+// Set is a helper function to set a value in the map.
+// If the key already exists, it keeps its position. Otherwise, it is added at the end of the order.
 func Set[M ~map[K]V, K comparable, V any](
 	m *M, key K, v V,
 	getIndex func(V) int,
@@ -17,12 +19,25 @@ func Set[M ~map[K]V, K comparable, V any](
 		return
 	}
 
+	// keep the position of an existing key
+	if old, ok := (*m)[key]; ok {
+		if idx := getIndex(old); idx != 0 {
+			(*m)[key] = setIndex(v, idx)
+			return
+		}
+	}
+
+	(*m)[key] = setIndex(v, highestIndex(*m, getIndex)+1)
+}
+
+// highestIndex returns the highest index in the map or zero if the map is empty.
+func highestIndex[M ~map[K]V, K comparable, V any](m M, getIndex func(V) int) int {
 	highestIdx := 0
-	for _, v := range *m {
+	for _, v := range m {
 		if idx := getIndex(v); idx > highestIdx {
 			highestIdx = idx
 		}
 	}
 
-	(*m)[key] = setIndex(v, highestIdx+1)
+	return highestIdx
 }
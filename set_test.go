@@ -42,3 +42,70 @@ func TestSet(t *testing.T) {
 		i++
 	}
 }
+
+func TestSet_Existing(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		var om UserDefinedOrderedMap
+		om.Set("foo", &ValueWithIndex{Foo: "a"})
+		om.Set("bar", &ValueWithIndex{Foo: "b"})
+		om.Set("baz", &ValueWithIndex{Foo: "c"})
+		om.Set("foo", &ValueWithIndex{Foo: "d"})
+
+		testSetExisting(t, om, func(v *ValueWithIndex) string { return v.Foo })
+	})
+
+	t.Run("ordered map", func(t *testing.T) {
+		var om OrderedMap
+		om.Set("foo", Value{Foo: "a"})
+		om.Set("bar", Value{Foo: "b"})
+		om.Set("baz", Value{Foo: "c"})
+		om.Set("foo", Value{Foo: "d"})
+
+		testSetExisting(t, om, func(v Value) string { return v.Foo })
+	})
+
+	t.Run("ordered map with uninitialized key", func(t *testing.T) {
+		var om OrderedMap
+		om.Set("foo", Value{Foo: "a"})
+		om["bar"] = ordmap.Value[Value]{V: Value{Foo: "b"}}
+		om.Set("baz", Value{Foo: "c"})
+		om.Set("bar", Value{Foo: "d"})
+
+		keys := []string{"foo", "baz", "bar"}
+
+		i := 0
+		for k := range om.ByIndex() {
+			if k != keys[i] {
+				t.Fatalf("got: %v, want: %v", k, keys[i])
+			}
+
+			i++
+		}
+	})
+}
+
+func testSetExisting[V any](t *testing.T, om ordmap.ByIndexer[string, V], foo func(V) string) {
+	t.Helper()
+
+	keys := []string{"foo", "bar", "baz"}
+	values := []string{"d", "b", "c"}
+
+	i := 0
+	for k, v := range om.ByIndex() {
+		if k != keys[i] {
+			t.Fatalf("got: %v, want: %v", k, keys[i])
+		}
+
+		if got := foo(v); got != values[i] {
+			t.Fatalf("got: %v, want: %v", got, values[i])
+		}
+
+		i++
+	}
+
+	if i != len(keys) {
+		t.Fatalf("got: %d, want: %d", i, len(keys))
+	}
+}
@@ -22,10 +22,35 @@ func SortFunc[M ~map[K]V, K comparable, V any](m M, setIndex func(V, int) V, les
 	doSort(m, setIndex, func(keys []K) { slices.SortFunc(keys, less) })
 }
 
+// SortStableFunc sorts the map by key-value pair using a custom comparison function and sets the indices accordingly.
+// Pairs that compare as equal keep their current order.
+func (om OrderedMap[K, V]) SortStableFunc(compare func(K, V, K, V) int) {
+	SortStableFunc(om, getIndex, setIndex, func(a K, va Value[V], b K, vb Value[V]) int {
+		return compare(a, va.V, b, vb.V)
+	})
+}
+
+// SortStableFunc is a helper function to sort a map by key-value pair using a custom comparison function
+// and set the indices accordingly. Pairs that compare as equal keep their current order.
+func SortStableFunc[M ~map[K]V, K comparable, V any](
+	m M,
+	getIndex func(V) int,
+	setIndex func(V, int) V,
+	compare func(K, V, K, V) int,
+) {
+	keys := orderedKeys(m, getIndex)
+	slices.SortStableFunc(keys, func(a, b K) int { return compare(a, m[a], b, m[b]) })
+	setIndices(m, keys, setIndex)
+}
+
 func doSort[M ~map[K]V, K comparable, V any](m M, setIndex func(V, int) V, sortKeys func([]K)) {
 	keys := maps.Keys(m)
 	sortKeys(keys)
+	setIndices(m, keys, setIndex)
+}
 
+// setIndices sets the indices of the map so that they follow the order of the given keys.
+func setIndices[M ~map[K]V, K comparable, V any](m M, keys []K, setIndex func(V, int) V) {
 	for i, key := range keys {
 		m[key] = setIndex(m[key], i+1)
 	}
@@ -2,6 +2,7 @@ package ordmap_test
 
 import (
 	"cmp"
+	"slices"
 	"testing"
 
 	"github.com/MarkRosemaker/ordmap"
@@ -102,3 +103,70 @@ func testSort[V any](t *testing.T, om ordmap.ByIndexer[string, V], sort func())
 		i++
 	}
 }
+
+func (om UserDefinedOrderedMap) SortStableFunc(compare func(string, *ValueWithIndex, string, *ValueWithIndex) int) {
+	ordmap.SortStableFunc(om, getIndex, setIndex, compare)
+}
+
+func TestSortStableFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		var om UserDefinedOrderedMap
+
+		om.SortStableFunc(nil) // no panic
+
+		for _, k := range []string{"e", "d", "c", "b", "a"} {
+			om.Set(k, &ValueWithIndex{Bar: len(om) % 2})
+		}
+
+		om.SortStableFunc(func(_ string, a *ValueWithIndex, _ string, b *ValueWithIndex) int {
+			return cmp.Compare(a.Bar, b.Bar)
+		})
+
+		if got, want := collectKeys(om), []string{"e", "c", "a", "d", "b"}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+
+		for i, k := range []string{"e", "c", "a", "d", "b"} {
+			if om[k].idx != i+1 {
+				t.Fatalf("got: %v, want: %v", om[k].idx, i+1)
+			}
+		}
+	})
+
+	t.Run("ordered map", func(t *testing.T) {
+		var om OrderedMap
+		om.Set("a", Value{Foo: "y", Bar: 2})
+		om.Set("b", Value{Foo: "x", Bar: 1})
+		om.Set("c", Value{Foo: "y", Bar: 1})
+		om.Set("d", Value{Foo: "x", Bar: 2})
+
+		// sort by bar, the previous order is kept for ties
+		om.SortStableFunc(func(_ string, a Value, _ string, b Value) int {
+			return cmp.Compare(a.Bar, b.Bar)
+		})
+
+		if got, want := collectKeys(om), []string{"b", "c", "a", "d"}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+
+		// sorting stably by foo afterwards sorts by foo, and by bar for the same foo
+		om.SortStableFunc(func(_ string, a Value, _ string, b Value) int {
+			return cmp.Compare(a.Foo, b.Foo)
+		})
+
+		if got, want := collectKeys(om), []string{"b", "d", "c", "a"}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+
+		// the key can be compared as well
+		om.SortStableFunc(func(a string, _ Value, b string, _ Value) int {
+			return -cmp.Compare(a, b)
+		})
+
+		if got, want := collectKeys(om), []string{"d", "c", "b", "a"}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+	})
+}
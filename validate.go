@@ -0,0 +1,66 @@
+package ordmap
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MarkRosemaker/errpath"
+)
+
+// Validate reports every key whose index does not fit into a dense order from 1 to the length of the map.
+func (om OrderedMap[K, V]) Validate() error { return Validate(om, getIndex) }
+
+// Normalize sets the indices to a dense order from 1 to the length of the map, keeping the current order.
+func (om OrderedMap[K, V]) Normalize() { Normalize(om, getIndex, setIndex) }
+
+// Validate is a helper function to check the indices of a map.
+// It reports every key whose index is not set, negative, not unique or leaves a gap
+// as an error of type *errpath.ErrKey, joined in the current order of the map.
+func Validate[M ~map[K]V, K comparable, V any](m M, getIndex func(V) int) error {
+	count := make(map[int]int, len(m))
+	for _, v := range m {
+		count[getIndex(v)]++
+	}
+
+	var errs []error
+
+	prev := 0 // the previous positive index
+	for _, k := range orderedKeys(m, getIndex) {
+		idx := getIndex(m[k])
+
+		var msg string
+		switch {
+		case idx == 0:
+			msg = "index is not set"
+		case idx < 0:
+			msg = "index is negative"
+		case count[idx] > 1:
+			msg = "index is not unique"
+		case idx != prev+1:
+			msg = fmt.Sprintf("index leaves a gap after %d", prev)
+		}
+
+		if idx > 0 {
+			prev = idx
+		}
+
+		if msg != "" {
+			errs = append(errs, &errpath.ErrKey{
+				Key: fmt.Sprint(k),
+				Err: &errpath.ErrInvalid[int]{Value: idx, Message: msg},
+			})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Normalize is a helper function to set the indices of a map to a dense order from 1 to the length of the map,
+// keeping the current order.
+func Normalize[M ~map[K]V, K comparable, V any](
+	m M,
+	getIndex func(V) int,
+	setIndex func(V, int) V,
+) {
+	setIndices(m, orderedKeys(m, getIndex), setIndex)
+}
@@ -0,0 +1,132 @@
+package ordmap_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/MarkRosemaker/errpath"
+	"github.com/MarkRosemaker/ordmap"
+)
+
+func (om UserDefinedOrderedMap) Validate() error {
+	return ordmap.Validate(om, getIndex)
+}
+
+func (om UserDefinedOrderedMap) Normalize() {
+	ordmap.Normalize(om, getIndex, setIndex)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user defined ordered map", func(t *testing.T) {
+		var om UserDefinedOrderedMap
+		if err := om.Validate(); err != nil {
+			t.Fatal(err)
+		}
+
+		om = UserDefinedOrderedMap{
+			"a": &ValueWithIndex{idx: -3},
+			"b": &ValueWithIndex{idx: 1},
+			"c": &ValueWithIndex{idx: 2},
+			"d": &ValueWithIndex{idx: 2},
+			"e": &ValueWithIndex{idx: 5},
+			"f": &ValueWithIndex{idx: 6},
+			"g": &ValueWithIndex{},
+		}
+
+		err := om.Validate()
+
+		var joined interface{ Unwrap() []error }
+		if !errors.As(err, &joined) {
+			t.Fatalf("got: %T", err)
+		}
+
+		want := []struct {
+			key string
+			idx int
+			msg string
+		}{
+			{"a", -3, "index is negative"},
+			{"c", 2, "index is not unique"},
+			{"d", 2, "index is not unique"},
+			{"e", 5, "index leaves a gap after 2"},
+			{"g", 0, "index is not set"},
+		}
+
+		errs := joined.Unwrap()
+		if len(errs) != len(want) {
+			t.Fatalf("got: %v", err)
+		}
+
+		for i, w := range want {
+			errKey := errAs[errpath.ErrKey](t, errs[i])
+			if errKey.Key != w.key {
+				t.Fatalf("got: %v, want: %v", errKey.Key, w.key)
+			}
+
+			errInvalid := errAs[errpath.ErrInvalid[int]](t, errKey.Err)
+			if errInvalid.Value != w.idx || errInvalid.Message != w.msg {
+				t.Fatalf("got: %v, want: %v", errInvalid, w)
+			}
+		}
+
+		const wantErr = `["a"] (-3) is invalid: index is negative
+["c"] (2) is invalid: index is not unique
+["d"] (2) is invalid: index is not unique
+["e"] (5) is invalid: index leaves a gap after 2
+["g"] (0) is invalid: index is not set`
+		if err.Error() != wantErr {
+			t.Fatalf("got: %v, want: %v", err, wantErr)
+		}
+
+		keys := collectKeys(om)
+
+		om.Normalize()
+
+		if err := om.Validate(); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := collectKeys(om); !slices.Equal(got, keys) {
+			t.Fatalf("got: %v, want: %v", got, keys)
+		}
+
+		for i, k := range keys {
+			if om[k].idx != i+1 {
+				t.Fatalf("got: %v, want: %v", om[k].idx, i+1)
+			}
+		}
+	})
+
+	t.Run("ordered map", func(t *testing.T) {
+		var om OrderedMap
+		om.Set("a", Value{})
+		om.Set("b", Value{})
+		om.Set("c", Value{})
+
+		if err := om.Validate(); err != nil {
+			t.Fatal(err)
+		}
+
+		om.Delete("b")
+		om["d"] = ordmap.Value[Value]{}
+
+		err := om.Validate()
+		if want := `["c"] (3) is invalid: index leaves a gap after 1
+["d"] (0) is invalid: index is not set`; err == nil || err.Error() != want {
+			t.Fatalf("got: %v, want: %v", err, want)
+		}
+
+		om.Normalize()
+
+		if err := om.Validate(); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := collectKeys(om), []string{"a", "c", "d"}; !slices.Equal(got, want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+	})
+}
@@ -27,6 +27,16 @@ func (v Value[_]) MarshalJSONTo(enc *jsontext.Encoder) error {
 	return json.MarshalEncode(enc, v.V, enc.Options())
 }
 
+// indexed is implemented by Value, which lets reflection recognize it and access its index.
+type indexed interface {
+	index() int
+	withIndex(i int) any
+}
+
+func (v Value[_]) index() int { return v.idx }
+
+func (v Value[_]) withIndex(i int) any { v.idx = i; return v }
+
 // getIndex returns the index of a value.
 func getIndex[V any](v Value[V]) int { return v.idx }
 